@@ -0,0 +1,99 @@
+// Package apierror writes every error response in the same JSON envelope:
+//
+//	{
+//	  "error": {
+//	    "code": "start_date_in_past",
+//	    "message": "start date cannot be in the past",
+//	    "details": [{"field": "start_date", "message": "start date cannot be in the past"}],
+//	    "request_id": "4f1c2b7e9a0d3e85"
+//	  }
+//	}
+//
+// Handlers pass domain errors to Write unchanged; the table in domain.go
+// decides their status and code. Errors that only make sense at the HTTP
+// layer, such as a malformed body, are built with New and the helpers below.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// RequestIDHeader carries the request ID set by middlewares.RequestID. It is
+// echoed in every error body so a report can be matched to the server log.
+const RequestIDHeader = "X-Request-ID"
+
+// Error is an error with the status and code to report it with.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details []FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// FieldError points at the request field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type body struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// InvalidBody is for request bodies that are not the expected JSON.
+func InvalidBody() *Error {
+	return New(http.StatusBadRequest, "invalid_body", "invalid request body")
+}
+
+// InvalidField is for a request field or query parameter the handler could
+// not parse.
+func InvalidField(field string, err error) *Error {
+	e := New(http.StatusBadRequest, "invalid_field", err.Error())
+	e.Details = []FieldError{{Field: field, Message: err.Error()}}
+	return e
+}
+
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, "unauthorized", message)
+}
+
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, "forbidden", message)
+}
+
+// Write sends err as a JSON error response. Errors it does not recognise are
+// logged and reported as a bare 500 so internals don't leak to clients.
+func Write(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = fromDomain(err)
+	}
+	requestID := w.Header().Get(RequestIDHeader)
+	if e.Status >= http.StatusInternalServerError {
+		log.Printf("request %s: %v", requestID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(map[string]body{"error": {
+		Code:      e.Code,
+		Message:   e.Message,
+		Details:   e.Details,
+		RequestID: requestID,
+	}})
+}
@@ -0,0 +1,126 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/catalog"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/user"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+		details []FieldError
+	}{
+		{
+			name:   "not found",
+			err:    auction.ErrNotFound,
+			status: http.StatusNotFound, code: "auction_not_found", message: "auction not found",
+		},
+		{
+			name:   "wrapped",
+			err:    fmt.Errorf("%w: line 3: bad rate", fx.ErrInvalidRate),
+			status: http.StatusBadRequest, code: "invalid_exchange_rate", message: "invalid exchange rate: line 3: bad rate",
+		},
+		{
+			name:   "with a field",
+			err:    auction.ErrStartInPast,
+			status: http.StatusBadRequest, code: "start_date_in_past", message: "start date cannot be in the past",
+			details: []FieldError{{Field: "start_date", Message: "start date cannot be in the past"}},
+		},
+		{
+			name:   "conflict",
+			err:    lot.ErrHasAuction,
+			status: http.StatusConflict, code: "lot_has_auction", message: lot.ErrHasAuction.Error(),
+		},
+		{
+			name:   "amount too large",
+			err:    money.ErrTooLarge,
+			status: http.StatusUnprocessableEntity, code: "amount_too_large", message: "amount is too large",
+		},
+		{
+			name:   "invalid cursor",
+			err:    catalog.ErrInvalidCursor,
+			status: http.StatusBadRequest, code: "invalid_cursor", message: "invalid cursor",
+			details: []FieldError{{Field: "cursor", Message: "invalid cursor"}},
+		},
+		{
+			name:   "bid too low",
+			err:    &bid.BidTooLowError{MinimumPerKG: money.New(151, "USD")},
+			status: http.StatusUnprocessableEntity, code: "bid_too_low", message: "bid must be at least 1.51 USD per kg",
+		},
+		{
+			name:   "missing fields",
+			err:    &user.MissingFieldsError{Fields: []string{"username", "password"}},
+			status: http.StatusBadRequest, code: "missing_fields", message: "username, password required",
+			details: []FieldError{
+				{Field: "username", Message: "username is required"},
+				{Field: "password", Message: "password is required"},
+			},
+		},
+		{
+			name:   "built by a handler",
+			err:    InvalidField("limit", errors.New("must be a number")),
+			status: http.StatusBadRequest, code: "invalid_field", message: "must be a number",
+			details: []FieldError{{Field: "limit", Message: "must be a number"}},
+		},
+		{
+			name:   "forbidden",
+			err:    Forbidden("sellers only"),
+			status: http.StatusForbidden, code: "forbidden", message: "sellers only",
+		},
+		{
+			name:   "unknown",
+			err:    errors.New("pq: connection refused"),
+			status: http.StatusInternalServerError, code: "internal_error", message: "internal server error",
+		},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		w.Header().Set(RequestIDHeader, "abc123")
+		Write(w, tt.err)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s: Content-Type = %q", tt.name, ct)
+		}
+		var got map[string]body
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Errorf("%s: decoding %q: %v", tt.name, w.Body.String(), err)
+			continue
+		}
+		want := body{Code: tt.code, Message: tt.message, Details: tt.details, RequestID: "abc123"}
+		if e := got["error"]; !reflect.DeepEqual(e, want) {
+			t.Errorf("%s: body = %+v, want %+v", tt.name, e, want)
+		}
+	}
+}
+
+func TestDomainErrorsAreDistinct(t *testing.T) {
+	// Every entry must be reachable: an error matched by an earlier entry
+	// would never get its own status and code.
+	for i, d := range domainErrors {
+		for _, earlier := range domainErrors[:i] {
+			if errors.Is(d.err, earlier.err) {
+				t.Errorf("%q (%s) is shadowed by %q (%s)", d.err, d.code, earlier.err, earlier.code)
+			}
+		}
+	}
+}
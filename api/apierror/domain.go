@@ -0,0 +1,124 @@
+package apierror
+
+import (
+	"errors"
+	"net/http"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/catalog"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/settlement"
+	"banana-auction/internal/domain/user"
+)
+
+// domainErrors gives each domain error its status and code, and for
+// validation errors the request field it is about.
+var domainErrors = []struct {
+	err    error
+	status int
+	code   string
+	field  string
+}{
+	{user.ErrUsernameTaken, http.StatusConflict, "username_taken", "username"},
+	{user.ErrInvalidRole, http.StatusBadRequest, "invalid_role", "role"},
+	{user.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials", ""},
+	{user.ErrInvalidRefreshToken, http.StatusUnauthorized, "invalid_refresh_token", "refresh_token"},
+	{user.ErrRefreshTokenReused, http.StatusUnauthorized, "refresh_token_reused", "refresh_token"},
+	{user.ErrNotFound, http.StatusNotFound, "user_not_found", ""},
+
+	{lot.ErrNotFound, http.StatusNotFound, "lot_not_found", ""},
+	{lot.ErrNotOwner, http.StatusForbidden, "not_lot_owner", ""},
+	{lot.ErrTooLight, http.StatusBadRequest, "lot_too_light", "total_weight_kg"},
+	{lot.ErrHarvestRequired, http.StatusBadRequest, "harvest_date_required", "harvest_date"},
+	{lot.ErrHarvestInFuture, http.StatusBadRequest, "harvest_date_in_future", "harvest_date"},
+	{lot.ErrSettled, http.StatusConflict, "lot_settled", ""},
+	{lot.ErrAuctionStarted, http.StatusConflict, "lot_auction_started", ""},
+	{lot.ErrHasAuction, http.StatusConflict, "lot_has_auction", ""},
+
+	{auction.ErrNotFound, http.StatusNotFound, "auction_not_found", ""},
+	{auction.ErrAlreadyExists, http.StatusConflict, "auction_exists", "lot_id"},
+	{auction.ErrNotEditable, http.StatusConflict, "auction_not_editable", ""},
+	{auction.ErrBiddingStarted, http.StatusConflict, "bidding_started", ""},
+	{auction.ErrInvalidTransition, http.StatusConflict, "invalid_status_transition", ""},
+	{auction.ErrCancelReasonRequired, http.StatusBadRequest, "reason_required", "reason"},
+	{auction.ErrStartRequired, http.StatusBadRequest, "start_date_required", "start_date"},
+	{auction.ErrStartInPast, http.StatusBadRequest, "start_date_in_past", "start_date"},
+	{auction.ErrDurationTooShort, http.StatusBadRequest, "duration_too_short", "duration_days"},
+	{auction.ErrUnknownCurrency, http.StatusBadRequest, "unknown_currency", "currency"},
+	{auction.ErrCurrencyMismatch, http.StatusBadRequest, "currency_mismatch", ""},
+	{auction.ErrUnknownType, http.StatusBadRequest, "unknown_auction_type", "auction_type"},
+	{auction.ErrUnknownClearing, http.StatusBadRequest, "unknown_clearing", "clearing"},
+	{auction.ErrSoftCloseNotEnglish, http.StatusBadRequest, "soft_close_not_supported", "soft_close_window_minutes"},
+	{auction.ErrNegativeSoftClose, http.StatusBadRequest, "invalid_soft_close", ""},
+	{auction.ErrIncompleteSoftClose, http.StatusBadRequest, "invalid_soft_close", ""},
+	{auction.ErrInvalidDutchFloor, http.StatusBadRequest, "invalid_dutch_floor", "dutch_floor_price_per_kg"},
+	{auction.ErrInvalidDutchDecrement, http.StatusBadRequest, "invalid_dutch_decrement", ""},
+	{auction.ErrNegativePrice, http.StatusBadRequest, "negative_price", ""},
+	{auction.ErrReserveOnDutch, http.StatusBadRequest, "reserve_not_supported", "reserve_price_per_kg"},
+	{auction.ErrBuyNowNotEnglish, http.StatusBadRequest, "buy_now_not_supported", "buy_now_price_per_kg"},
+	{auction.ErrBuyNowOutOfRange, http.StatusBadRequest, "invalid_buy_now", "buy_now_price_per_kg"},
+
+	{bid.ErrNotFound, http.StatusNotFound, "bid_not_found", ""},
+	{bid.ErrProxyNotFound, http.StatusNotFound, "proxy_bid_not_found", ""},
+	{bid.ErrAuctionNotStarted, http.StatusConflict, "auction_not_started", ""},
+	{bid.ErrAuctionEnded, http.StatusConflict, "auction_ended", ""},
+	{bid.ErrAuctionCancelled, http.StatusConflict, "auction_cancelled", ""},
+	{bid.ErrProxyNotSupported, http.StatusConflict, "proxy_not_supported", ""},
+	{bid.ErrAcceptOnly, http.StatusConflict, "accept_only", ""},
+	{bid.ErrNoAcceptPrice, http.StatusConflict, "no_accept_price", ""},
+	{bid.ErrBuyNowUnavailable, http.StatusConflict, "buy_now_unavailable", ""},
+	{bid.ErrQuantityNotUsed, http.StatusConflict, "quantity_not_used", "quantity_kg"},
+	{bid.ErrInvalidPrice, http.StatusUnprocessableEntity, "invalid_price", ""},
+	{bid.ErrWrongCurrency, http.StatusUnprocessableEntity, "wrong_currency", ""},
+	{bid.ErrProxyLowered, http.StatusUnprocessableEntity, "proxy_lowered", "max_price_per_kg"},
+	{bid.ErrBidLowered, http.StatusUnprocessableEntity, "bid_lowered", ""},
+	{bid.ErrInvalidQuantity, http.StatusUnprocessableEntity, "invalid_quantity", "quantity_kg"},
+
+	{settlement.ErrNotFound, http.StatusNotFound, "settlement_not_found", ""},
+	{settlement.ErrAuctionNotClosed, http.StatusConflict, "auction_not_closed", ""},
+	{settlement.ErrAlreadyExists, http.StatusConflict, "already_settled", ""},
+
+	{catalog.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor", "cursor"},
+	{catalog.ErrInvalidFilter, http.StatusBadRequest, "invalid_filter", ""},
+
+	{fx.ErrInvalidRate, http.StatusBadRequest, "invalid_exchange_rate", ""},
+	{fx.ErrNotFound, http.StatusNotFound, "exchange_rate_not_found", ""},
+
+	{money.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount", ""},
+	{money.ErrTooManyDecimals, http.StatusBadRequest, "too_many_decimals", ""},
+	{money.ErrTooLarge, http.StatusUnprocessableEntity, "amount_too_large", ""},
+	{money.ErrCurrencyMismatch, http.StatusUnprocessableEntity, "currency_mismatch", ""},
+	{money.ErrUnknownCurrency, http.StatusBadRequest, "unknown_currency", "currency"},
+	{money.ErrInvalidRate, http.StatusBadRequest, "invalid_rate", ""},
+}
+
+// fromDomain looks err up in domainErrors and the domain's typed errors.
+func fromDomain(err error) *Error {
+	var tooLow *bid.BidTooLowError
+	if errors.As(err, &tooLow) {
+		return New(http.StatusUnprocessableEntity, "bid_too_low", err.Error())
+	}
+	var missing *user.MissingFieldsError
+	if errors.As(err, &missing) {
+		e := New(http.StatusBadRequest, "missing_fields", err.Error())
+		for _, f := range missing.Fields {
+			e.Details = append(e.Details, FieldError{Field: f, Message: f + " is required"})
+		}
+		return e
+	}
+
+	for _, d := range domainErrors {
+		if !errors.Is(err, d.err) {
+			continue
+		}
+		e := New(d.status, d.code, err.Error())
+		if d.field != "" {
+			e.Details = []FieldError{{Field: d.field, Message: err.Error()}}
+		}
+		return e
+	}
+	return New(http.StatusInternalServerError, "internal_error", "internal server error")
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"banana-auction/api/apierror"
+	"banana-auction/internal/domain/money"
+)
+
+// Amounts travel as decimal strings such as "12.50". Plain JSON numbers are
+// accepted too and are read digit by digit, never through a float. An empty
+// field parses to the zero Amount so the services can tell it was left out.
+
+// parseAmount reads the request field name as an amount in c.
+func parseAmount(name string, n json.Number, c money.Currency) (money.Amount, error) {
+	if n == "" {
+		return money.Amount{}, nil
+	}
+	a, err := money.Parse(n.String(), c)
+	if err != nil {
+		return money.Amount{}, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return a, nil
+}
+
+// amountField is a request amount and where its parsed value goes.
+type amountField struct {
+	name string
+	src  json.Number
+	dst  *money.Amount
+}
+
+// parseAmounts parses every field in c. It writes the error response and
+// returns false if one is not a valid amount.
+func parseAmounts(w http.ResponseWriter, c money.Currency, fields ...amountField) bool {
+	for _, f := range fields {
+		a, err := parseAmount(f.name, f.src, c)
+		if err != nil {
+			apierror.Write(w, apierror.InvalidField(f.name, err))
+			return false
+		}
+		*f.dst = a
+	}
+	return true
+}
@@ -5,58 +5,112 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"banana-auction/api/apierror"
 	"banana-auction/api/middlewares"
 	"banana-auction/internal/domain/auction"
 	"banana-auction/internal/domain/bid"
 	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
 )
 
 type AuctionHandler struct {
 	svc    auction.Service
 	lotSvc lot.Service
 	bidSvc bid.Service
+	// currency is what new auctions are priced in unless the seller picks
+	// another.
+	currency money.Currency
 }
 
-func NewAuctionHandler(svc auction.Service, lotSvc lot.Service, bidSvc bid.Service) *AuctionHandler {
-	return &AuctionHandler{svc: svc, lotSvc: lotSvc, bidSvc: bidSvc}
+func NewAuctionHandler(svc auction.Service, lotSvc lot.Service, bidSvc bid.Service, currency money.Currency) *AuctionHandler {
+	return &AuctionHandler{svc: svc, lotSvc: lotSvc, bidSvc: bidSvc, currency: currency}
 }
 
 func (h *AuctionHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userID, err := middlewares.GetUserID(r)
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var req struct {
-		LotID             int     `json:"lot_id"`
-		StartDate         string  `json:"start_date"`
-		DurationDays      int     `json:"duration_days"`
-		InitialPricePerKG float64 `json:"initial_price_per_kg"`
+		LotID                        int         `json:"lot_id"`
+		StartDate                    string      `json:"start_date"`
+		DurationDays                 int         `json:"duration_days"`
+		Currency                     string      `json:"currency"`
+		InitialPricePerKG            json.Number `json:"initial_price_per_kg"`
+		ReservePricePerKG            json.Number `json:"reserve_price_per_kg"`
+		BuyNowPricePerKG             json.Number `json:"buy_now_price_per_kg"`
+		AuctionType                  string      `json:"auction_type"`
+		DutchFloorPricePerKG         json.Number `json:"dutch_floor_price_per_kg"`
+		DutchDecrementPerKG          json.Number `json:"dutch_decrement_per_kg"`
+		DutchDecrementMinutes        int         `json:"dutch_decrement_interval_minutes"`
+		Clearing                     string      `json:"clearing"`
+		SoftCloseWindowMinutes       int         `json:"soft_close_window_minutes"`
+		SoftCloseExtensionMinutes    int         `json:"soft_close_extension_minutes"`
+		SoftCloseMaxExtensionMinutes int         `json:"soft_close_max_extension_minutes"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, apierror.InvalidBody())
+		return
+	}
+
+	start, err := parseStartDate(req.StartDate)
+	if err != nil {
+		apierror.Write(w, apierror.InvalidField("start_date", err))
+		return
+	}
+
+	a := auction.Auction{
+		LotID:        req.LotID,
+		StartDate:    start,
+		DurationDays: req.DurationDays,
+		Currency:     h.currency,
+		Type:         auction.Type(req.AuctionType),
+		SoftClose: auction.SoftClose{
+			WindowMinutes:       req.SoftCloseWindowMinutes,
+			ExtensionMinutes:    req.SoftCloseExtensionMinutes,
+			MaxExtensionMinutes: req.SoftCloseMaxExtensionMinutes,
+		},
+		Dutch:    auction.Dutch{DecrementIntervalMinutes: req.DutchDecrementMinutes},
+		Clearing: auction.Clearing(req.Clearing),
+	}
+	if req.Currency != "" {
+		a.Currency = money.Currency(strings.ToUpper(req.Currency))
+	}
+	if !a.Currency.Valid() {
+		apierror.Write(w, auction.ErrUnknownCurrency)
+		return
+	}
+	if !parseAmounts(w, a.Currency,
+		amountField{"initial_price_per_kg", req.InitialPricePerKG, &a.InitialPricePerKG},
+		amountField{"reserve_price_per_kg", req.ReservePricePerKG, &a.ReservePricePerKG},
+		amountField{"buy_now_price_per_kg", req.BuyNowPricePerKG, &a.BuyNowPricePerKG},
+		amountField{"dutch_floor_price_per_kg", req.DutchFloorPricePerKG, &a.Dutch.FloorPricePerKG},
+		amountField{"dutch_decrement_per_kg", req.DutchDecrementPerKG, &a.Dutch.DecrementPerKG},
+	) {
 		return
 	}
 
 	// Fetch the lot to verify the seller
-	lot, err := h.lotSvc.GetLot(req.LotID)
+	lot, err := h.lotSvc.GetLot(r.Context(), req.LotID)
 	if err != nil {
-		http.Error(w, "Lot not found", http.StatusNotFound)
+		apierror.Write(w, err)
 		return
 	}
 
 	// Check if the user is the seller of the lot
 	if lot.SellerID != userID {
-		http.Error(w, "Only the seller of the lot can create an auction", http.StatusForbidden)
+		apierror.Write(w, apierror.Forbidden("Only the seller of the lot can create an auction"))
 		return
 	}
 
 	// Create the auction
-	id, err := h.svc.CreateAuction(req.LotID, req.StartDate, req.DurationDays, req.InitialPricePerKG)
+	id, err := h.svc.CreateAuction(r.Context(), a)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, err)
 		return
 	}
 
@@ -67,93 +121,375 @@ func (h *AuctionHandler) ListBids(w http.ResponseWriter, r *http.Request) {
 	// Extract auctionID from the path (e.g., /auctions/7/bids/)
 	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	if len(pathParts) < 3 {
-		http.Error(w, "Invalid URL format. Use /auctions/{auctionID}/bids", http.StatusBadRequest)
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_path", "Invalid URL format. Use /auctions/{auctionID}/bids"))
 		return
 	}
 
 	lastSegment := pathParts[len(pathParts)-1]
 	if lastSegment != "bids" {
-		http.Error(w, "Invalid URL format. Expected /auctions/{auctionID}/bids", http.StatusBadRequest)
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_path", "Invalid URL format. Expected /auctions/{auctionID}/bids"))
 		return
 	}
 
 	auctionIDStr := pathParts[len(pathParts)-2] 
 	auctionID, err := strconv.Atoi(auctionIDStr)
 	if err != nil {
-		http.Error(w, "Invalid auction ID", http.StatusBadRequest)
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid auction ID"))
 		return
 	}
 
 	userID, err := middlewares.GetUserID(r)
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	// Fetch the auction
-	auction, err := h.svc.GetAuction(auctionID)
+	auction, err := h.svc.GetAuction(r.Context(), auctionID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		apierror.Write(w, err)
 		return
 	}
 
 	// Fetch the associated lot
-	lot, err := h.lotSvc.GetLot(auction.LotID)
+	lot, err := h.lotSvc.GetLot(r.Context(), auction.LotID)
 	if err != nil {
-		http.Error(w, "Lot not found", http.StatusInternalServerError)
+		apierror.Write(w, err)
 		return
 	}
 
 	// Check if the user is the seller of the lot
 	if lot.SellerID != userID {
-		http.Error(w, "Only the seller can list bids for this auction", http.StatusForbidden)
+		apierror.Write(w, apierror.Forbidden("Only the seller can list bids for this auction"))
+		return
+	}
+
+	// Sealed bids stay hidden from everyone, the seller included, until close
+	if auction.Type.Sealed() && !auction.Status.Ended() {
+		apierror.Write(w, apierror.Forbidden("Bids are sealed until the auction closes"))
 		return
 	}
 
 	// List bids for the auction using bid service
-	bids, err := h.bidSvc.ListBids(auctionID)
+	bids, err := h.bidSvc.ListBids(r.Context(), auctionID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, err)
 		return
 	}
 
 	json.NewEncoder(w).Encode(bids)
 }
 
+// GetAuction shows an auction to any signed-in user. Only the seller of its
+// lot sees the reserve and the dutch floor.
 func (h *AuctionHandler) GetAuction(w http.ResponseWriter, r *http.Request) {
 	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	idStr := pathParts[len(pathParts)-1]
 	auctionID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid auction ID", http.StatusBadRequest)
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid auction ID"))
 		return
 	}
 
 	userID, err := middlewares.GetUserID(r)
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	// Fetch the auction
-	auction, err := h.svc.GetAuction(auctionID)
+	auction, err := h.svc.GetAuction(r.Context(), auctionID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		apierror.Write(w, err)
 		return
 	}
 
-	// Fetch the associated lot
-	lot, err := h.lotSvc.GetLot(auction.LotID)
+	// Fetch the associated lot to tell the seller from everyone else
+	lot, err := h.lotSvc.GetLot(r.Context(), auction.LotID)
 	if err != nil {
-		http.Error(w, "Lot not found", http.StatusInternalServerError)
+		apierror.Write(w, err)
 		return
 	}
 
-	// Check if the user is the seller of the lot
-	if lot.SellerID != userID {
-		http.Error(w, "Only the seller can view this auction", http.StatusForbidden)
+	highest, err := h.bidSvc.GetHighestBid(r.Context(), auctionID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newAuctionResponse(auction, highest, lot.SellerID == userID))
+}
+
+// ListMine is the seller's dashboard of their own auctions.
+func (h *AuctionHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	userID, err := middlewares.GetUserID(r)
+	if err != nil {
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+		return
+	}
+
+	summaries, err := h.svc.ListSellerAuctions(r.Context(), userID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+	if summaries == nil {
+		summaries = []auction.Summary{}
+	}
+
+	json.NewEncoder(w).Encode(summaries)
+}
+
+func (h *AuctionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.ownAuction(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		StartDate                    *string      `json:"start_date"`
+		DurationDays                 *int         `json:"duration_days"`
+		InitialPricePerKG            *json.Number `json:"initial_price_per_kg"`
+		ReservePricePerKG            *json.Number `json:"reserve_price_per_kg"`
+		BuyNowPricePerKG             *json.Number `json:"buy_now_price_per_kg"`
+		AuctionType                  *string      `json:"auction_type"`
+		SoftCloseWindowMinutes       *int         `json:"soft_close_window_minutes"`
+		SoftCloseExtensionMinutes    *int         `json:"soft_close_extension_minutes"`
+		SoftCloseMaxExtensionMinutes *int         `json:"soft_close_max_extension_minutes"`
+		DutchFloorPricePerKG         *json.Number `json:"dutch_floor_price_per_kg"`
+		DutchDecrementPerKG          *json.Number `json:"dutch_decrement_per_kg"`
+		DutchDecrementMinutes        *int         `json:"dutch_decrement_interval_minutes"`
+		Clearing                     *string      `json:"clearing"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, apierror.InvalidBody())
+		return
+	}
+
+	// Prices stay in the currency the auction was created in
+	c := auction.Changes{DurationDays: req.DurationDays}
+	for _, p := range []struct {
+		name string
+		src  *json.Number
+		dst  **money.Amount
+	}{
+		{"initial_price_per_kg", req.InitialPricePerKG, &c.InitialPricePerKG},
+		{"reserve_price_per_kg", req.ReservePricePerKG, &c.ReservePricePerKG},
+		{"buy_now_price_per_kg", req.BuyNowPricePerKG, &c.BuyNowPricePerKG},
+	} {
+		if p.src == nil {
+			continue
+		}
+		*p.dst = new(money.Amount)
+		if !parseAmounts(w, a.Currency, amountField{p.name, *p.src, *p.dst}) {
+			return
+		}
+	}
+	if req.StartDate != nil {
+		start, err := parseStartDate(*req.StartDate)
+		if err != nil {
+			apierror.Write(w, apierror.InvalidField("start_date", err))
+			return
+		}
+		c.StartDate = &start
+	}
+	if req.AuctionType != nil {
+		t := auction.Type(*req.AuctionType)
+		c.Type = &t
+	}
+	if req.Clearing != nil {
+		cl := auction.Clearing(*req.Clearing)
+		c.Clearing = &cl
+	}
+	// Nested settings are patched field by field on top of the current ones
+	if req.SoftCloseWindowMinutes != nil || req.SoftCloseExtensionMinutes != nil || req.SoftCloseMaxExtensionMinutes != nil {
+		sc := a.SoftClose
+		setInt(&sc.WindowMinutes, req.SoftCloseWindowMinutes)
+		setInt(&sc.ExtensionMinutes, req.SoftCloseExtensionMinutes)
+		setInt(&sc.MaxExtensionMinutes, req.SoftCloseMaxExtensionMinutes)
+		c.SoftClose = &sc
+	}
+	if req.DutchFloorPricePerKG != nil || req.DutchDecrementPerKG != nil || req.DutchDecrementMinutes != nil {
+		d := a.Dutch
+		for _, p := range []struct {
+			name string
+			src  *json.Number
+			dst  *money.Amount
+		}{
+			{"dutch_floor_price_per_kg", req.DutchFloorPricePerKG, &d.FloorPricePerKG},
+			{"dutch_decrement_per_kg", req.DutchDecrementPerKG, &d.DecrementPerKG},
+		} {
+			if p.src != nil && !parseAmounts(w, a.Currency, amountField{p.name, *p.src, p.dst}) {
+				return
+			}
+		}
+		setInt(&d.DecrementIntervalMinutes, req.DutchDecrementMinutes)
+		c.Dutch = &d
+	}
+
+	updated, err := h.svc.UpdateAuction(r.Context(), a.ID, c)
+	if err != nil {
+		apierror.Write(w, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(auction)
+	highest, err := h.bidSvc.GetHighestBid(r.Context(), a.ID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newAuctionResponse(updated, highest, true))
+}
+
+// Cancel withdraws the auction. Bids are kept and live subscribers are told
+// the reason.
+func (h *AuctionHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.ownAuction(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, apierror.InvalidBody())
+		return
+	}
+
+	if err := h.svc.CancelAuction(r.Context(), a.ID, req.Reason); err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ownAuction loads the auction in /auctions/{auctionID} and checks that the
+// caller is its seller. It writes the error response and returns false if
+// the request must stop.
+func (h *AuctionHandler) ownAuction(w http.ResponseWriter, r *http.Request) (auction.Auction, bool) {
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	auctionID, err := strconv.Atoi(pathParts[len(pathParts)-1])
+	if err != nil {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid auction ID"))
+		return auction.Auction{}, false
+	}
+
+	userID, err := middlewares.GetUserID(r)
+	if err != nil {
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+		return auction.Auction{}, false
+	}
+
+	a, err := h.svc.GetAuction(r.Context(), auctionID)
+	if err != nil {
+		apierror.Write(w, err)
+		return auction.Auction{}, false
+	}
+
+	l, err := h.lotSvc.GetLot(r.Context(), a.LotID)
+	if err != nil {
+		apierror.Write(w, err)
+		return auction.Auction{}, false
+	}
+
+	if l.SellerID != userID {
+		apierror.Write(w, apierror.Forbidden("Only the seller can change this auction"))
+		return auction.Auction{}, false
+	}
+	return a, true
+}
+
+func setInt(dst *int, v *int) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+// auctionResponse is an auction as the API shows it. It adds the computed
+// end time, which includes any soft-close extension, the clock price of an
+// open dutch auction and whether the highest bid meets the reserve. The
+// reserve and the dutch floor are the seller's own and are left out for
+// everyone else.
+type auctionResponse struct {
+	ID                           int              `json:"id"`
+	LotID                        int              `json:"lot_id"`
+	AuctionType                  auction.Type     `json:"auction_type"`
+	Status                       auction.Status   `json:"status"`
+	StartDate                    time.Time        `json:"start_date"`
+	DurationDays                 int              `json:"duration_days"`
+	EndsAt                       time.Time        `json:"ends_at"`
+	Currency                     money.Currency   `json:"currency"`
+	InitialPricePerKG            money.Amount     `json:"initial_price_per_kg"`
+	ReservePricePerKG            *money.Amount    `json:"reserve_price_per_kg,omitempty"`
+	ReserveMet                   *bool            `json:"reserve_met,omitempty"`
+	BuyNowPricePerKG             *money.Amount    `json:"buy_now_price_per_kg,omitempty"`
+	CurrentPricePerKG            *money.Amount    `json:"current_price_per_kg,omitempty"`
+	DutchFloorPricePerKG         *money.Amount    `json:"dutch_floor_price_per_kg,omitempty"`
+	DutchDecrementPerKG          *money.Amount    `json:"dutch_decrement_per_kg,omitempty"`
+	DutchDecrementMinutes        int              `json:"dutch_decrement_interval_minutes,omitempty"`
+	Clearing                     auction.Clearing `json:"clearing,omitempty"`
+	SoftCloseWindowMinutes       int              `json:"soft_close_window_minutes,omitempty"`
+	SoftCloseExtensionMinutes    int              `json:"soft_close_extension_minutes,omitempty"`
+	SoftCloseMaxExtensionMinutes int              `json:"soft_close_max_extension_minutes,omitempty"`
+	ExtensionMinutes             int              `json:"extension_minutes"`
+	WinningBidID                 *int             `json:"winning_bid_id,omitempty"`
+	CancelReason                 string           `json:"cancel_reason,omitempty"`
+}
+
+// newAuctionResponse builds the response for a; owner is whether the reader
+// is the seller of its lot.
+func newAuctionResponse(a auction.Auction, highest *bid.Bid, owner bool) auctionResponse {
+	resp := auctionResponse{
+		ID:                           a.ID,
+		LotID:                        a.LotID,
+		AuctionType:                  a.Type,
+		Status:                       a.Status,
+		StartDate:                    a.StartDate,
+		DurationDays:                 a.DurationDays,
+		EndsAt:                       a.EndTime(),
+		Currency:                     a.Currency,
+		InitialPricePerKG:            a.InitialPricePerKG,
+		ReserveMet:                   reserveMet(a, highest),
+		BuyNowPricePerKG:             optionalAmount(a.BuyNowPricePerKG),
+		DutchDecrementPerKG:          optionalAmount(a.Dutch.DecrementPerKG),
+		DutchDecrementMinutes:        a.Dutch.DecrementIntervalMinutes,
+		Clearing:                     a.Clearing,
+		SoftCloseWindowMinutes:       a.SoftClose.WindowMinutes,
+		SoftCloseExtensionMinutes:    a.SoftClose.ExtensionMinutes,
+		SoftCloseMaxExtensionMinutes: a.SoftClose.MaxExtensionMinutes,
+		ExtensionMinutes:             a.ExtensionMinutes,
+		WinningBidID:                 a.WinningBidID,
+		CancelReason:                 a.CancelReason,
+	}
+	if owner {
+		resp.ReservePricePerKG = optionalAmount(a.ReservePricePerKG)
+		resp.DutchFloorPricePerKG = optionalAmount(a.Dutch.FloorPricePerKG)
+	}
+	if a.Type == auction.TypeDutch && !a.Status.Ended() {
+		price := a.PriceAt(time.Now())
+		resp.CurrentPricePerKG = &price
+	}
+	return resp
+}
+
+// optionalAmount is nil for an unset (zero) price so it is left out.
+func optionalAmount(a money.Amount) *money.Amount {
+	if a.IsZero() {
+		return nil
+	}
+	return &a
+}
+
+// reserveMet tells readers whether the reserve has been reached without
+// giving away the amount. It is nil when there is no reserve, and while
+// sealed bids are hidden.
+func reserveMet(a auction.Auction, highest *bid.Bid) *bool {
+	if a.ReservePricePerKG.IsZero() || (a.Type.Sealed() && !a.Status.Ended()) {
+		return nil
+	}
+	met := highest != nil && a.ReserveMet(highest.BidPricePerKG)
+	return &met
 }
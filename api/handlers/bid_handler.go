@@ -6,34 +6,40 @@ import (
 	"strconv"
 	"strings"
 
+	"banana-auction/api/apierror"
 	"banana-auction/api/middlewares"
+	"banana-auction/internal/domain/auction"
 	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
 )
 
 type BidHandler struct {
-	svc bid.Service
+	svc        bid.Service
+	auctionSvc auction.Service
+	lotSvc     lot.Service
 }
 
-func NewBidHandler(svc bid.Service) *BidHandler {
-	return &BidHandler{svc: svc}
+func NewBidHandler(svc bid.Service, auctionSvc auction.Service, lotSvc lot.Service) *BidHandler {
+	return &BidHandler{svc: svc, auctionSvc: auctionSvc, lotSvc: lotSvc}
 }
 
 func (h *BidHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apierror.Write(w, apierror.New(http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed"))
 		return
 	}
 
 	// Extract auctionID from the path
 	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	if len(pathParts) < 3 {
-		http.Error(w, "Invalid URL format. Use /auctions/{auctionID}/bids", http.StatusBadRequest)
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_path", "Invalid URL format. Use /auctions/{auctionID}/bids"))
 		return
 	}
 
 	lastSegment := pathParts[len(pathParts)-1]
 	if lastSegment != "bids" {
-		http.Error(w, "Invalid URL format. Expected /auctions/{auctionID}/bids", http.StatusBadRequest)
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_path", "Invalid URL format. Expected /auctions/{auctionID}/bids"))
 		return
 	}
 
@@ -42,27 +48,37 @@ func (h *BidHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
 	auctionID, err := strconv.Atoi(auctionIDStr)
 
 	if err != nil {
-		http.Error(w, "Invalid auction ID", http.StatusBadRequest)
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid auction ID"))
 		return
 	}
 
 	userID, err := middlewares.GetUserID(r)
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+		return
+	}
+
+	a, ok := h.checkNotSeller(w, r, auctionID, userID)
+	if !ok {
 		return
 	}
 
 	var req struct {
-		BidPricePerKG float64 `json:"bid_price_per_kg"`
+		BidPricePerKG json.Number `json:"bid_price_per_kg"`
+		QuantityKG    int         `json:"quantity_kg"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, apierror.InvalidBody())
+		return
+	}
+	var price money.Amount
+	if !parseAmounts(w, a.Currency, amountField{"bid_price_per_kg", req.BidPricePerKG, &price}) {
 		return
 	}
 
-	id, err := h.svc.PlaceBid(auctionID, userID, req.BidPricePerKG)
+	id, err := h.svc.PlaceBid(r.Context(), auctionID, userID, price, req.QuantityKG)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, err)
 		return
 	}
 
@@ -70,3 +86,168 @@ func (h *BidHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]int{"Bid placed successfully!!! bid_id": id})
 }
+
+func (h *BidHandler) PlaceProxyBid(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := proxyBidAuctionID(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := middlewares.GetUserID(r)
+	if err != nil {
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+		return
+	}
+
+	a, ok := h.checkNotSeller(w, r, auctionID, userID)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		MaxPricePerKG json.Number `json:"max_price_per_kg"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, apierror.InvalidBody())
+		return
+	}
+	var maxPrice money.Amount
+	if !parseAmounts(w, a.Currency, amountField{"max_price_per_kg", req.MaxPricePerKG, &maxPrice}) {
+		return
+	}
+
+	result, err := h.svc.PlaceProxyBid(r.Context(), auctionID, userID, maxPrice)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"proxy_id":             result.ProxyID,
+		"leading":              result.Leading,
+		"current_price_per_kg": result.CurrentPricePerKG,
+	})
+}
+
+// Accept takes the whole lot at a dutch auction's current clock price or at
+// the buy-it-now price.
+func (h *BidHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 3 || pathParts[len(pathParts)-1] != "accept" {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_path", "Invalid URL format. Use /auctions/{auctionID}/accept"))
+		return
+	}
+	auctionID, err := strconv.Atoi(pathParts[len(pathParts)-2])
+	if err != nil {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid auction ID"))
+		return
+	}
+
+	userID, err := middlewares.GetUserID(r)
+	if err != nil {
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+		return
+	}
+
+	if _, ok := h.checkNotSeller(w, r, auctionID, userID); !ok {
+		return
+	}
+
+	b, err := h.svc.Accept(r.Context(), auctionID, userID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"bid_id":       b.ID,
+		"price_per_kg": b.BidPricePerKG,
+	})
+}
+
+// GetProxyBid shows buyers their own maximum. Nobody else can read it.
+func (h *BidHandler) GetProxyBid(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := proxyBidAuctionID(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := middlewares.GetUserID(r)
+	if err != nil {
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+		return
+	}
+
+	p, err := h.svc.GetProxyBid(r.Context(), auctionID, userID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"proxy_id":         p.ID,
+		"auction_id":       p.AuctionID,
+		"max_price_per_kg": p.MaxPricePerKG,
+		"registered_at":    p.RegisteredAt,
+	})
+}
+
+// ListMine shows buyers every auction they have bid on and where they stand.
+func (h *BidHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	userID, err := middlewares.GetUserID(r)
+	if err != nil {
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+		return
+	}
+
+	summaries, err := h.svc.ListBuyerBids(r.Context(), userID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+	if summaries == nil {
+		summaries = []bid.Summary{}
+	}
+
+	json.NewEncoder(w).Encode(summaries)
+}
+
+// proxyBidAuctionID extracts auctionID from /auctions/{auctionID}/proxy-bid.
+func proxyBidAuctionID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 3 || pathParts[len(pathParts)-1] != "proxy-bid" {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_path", "Invalid URL format. Use /auctions/{auctionID}/proxy-bid"))
+		return 0, false
+	}
+	auctionID, err := strconv.Atoi(pathParts[len(pathParts)-2])
+	if err != nil {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid auction ID"))
+		return 0, false
+	}
+	return auctionID, true
+}
+
+// checkNotSeller fetches the auction and its lot to block self-bidding and
+// returns the auction. It writes the error response and returns false if
+// the request must stop.
+func (h *BidHandler) checkNotSeller(w http.ResponseWriter, r *http.Request, auctionID, userID int) (auction.Auction, bool) {
+	a, err := h.auctionSvc.GetAuction(r.Context(), auctionID)
+	if err != nil {
+		apierror.Write(w, err)
+		return auction.Auction{}, false
+	}
+
+	lot, err := h.lotSvc.GetLot(r.Context(), a.LotID)
+	if err != nil {
+		apierror.Write(w, err)
+		return auction.Auction{}, false
+	}
+
+	if lot.SellerID == userID {
+		apierror.Write(w, apierror.Forbidden("Sellers cannot bid on their own auctions"))
+		return auction.Auction{}, false
+	}
+	return a, true
+}
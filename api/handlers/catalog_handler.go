@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"banana-auction/api/apierror"
+	"banana-auction/internal/domain/catalog"
+	"banana-auction/internal/domain/money"
+)
+
+type CatalogHandler struct {
+	svc catalog.Service
+	// currency is what price filters are in unless the query names another.
+	currency money.Currency
+}
+
+func NewCatalogHandler(svc catalog.Service, currency money.Currency) *CatalogHandler {
+	return &CatalogHandler{svc: svc, currency: currency}
+}
+
+// Browse lists live and upcoming auctions for buyers. It is public, so
+// nothing in a listing identifies bidders or reveals the reserve.
+func (h *CatalogHandler) Browse(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := catalog.Filter{
+		Cultivar:       q.Get("cultivar"),
+		PlantedCountry: q.Get("planted_country"),
+		Sort:           catalog.Sort(q.Get("sort")),
+		Cursor:         q.Get("cursor"),
+		// Indicative prices come from the rate table; bids stay in each
+		// auction's own currency.
+		DisplayCurrency: money.Currency(strings.ToUpper(q.Get("display_currency"))),
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"harvest_from", &f.HarvestFrom}, {"harvest_to", &f.HarvestTo}} {
+		if !queryDate(w, q, p.name, p.dst) {
+			return
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"min_weight_kg", &f.MinWeightKG}, {"max_weight_kg", &f.MaxWeightKG}, {"limit", &f.Limit}} {
+		if !queryInt(w, q, p.name, p.dst) {
+			return
+		}
+	}
+	currency := h.currency
+	if v := q.Get("currency"); v != "" {
+		currency = money.Currency(strings.ToUpper(v))
+	}
+	if !parseAmounts(w, currency,
+		amountField{"min_price_per_kg", json.Number(q.Get("min_price_per_kg")), &f.MinPricePerKG},
+		amountField{"max_price_per_kg", json.Number(q.Get("max_price_per_kg")), &f.MaxPricePerKG},
+	) {
+		return
+	}
+
+	page, err := h.svc.Browse(r.Context(), f)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	if page.Listings == nil {
+		page.Listings = []catalog.Listing{}
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+// queryInt parses the optional query parameter name into dst. It writes the
+// error response and returns false if the value is not a number.
+func queryInt(w http.ResponseWriter, q url.Values, name string, dst *int) bool {
+	v := q.Get(name)
+	if v == "" {
+		return true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		apierror.Write(w, apierror.InvalidField(name, errors.New("Invalid "+name)))
+		return false
+	}
+	*dst = n
+	return true
+}
+
+// queryDate is queryInt for calendar days, given as RFC 3339 full-dates.
+func queryDate(w http.ResponseWriter, q url.Values, name string, dst *time.Time) bool {
+	v := q.Get(name)
+	if v == "" {
+		return true
+	}
+	d, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		apierror.Write(w, apierror.InvalidField(name, errors.New("Invalid "+name+", expected YYYY-MM-DD")))
+		return false
+	}
+	*dst = d
+	return true
+}
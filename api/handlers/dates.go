@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+)
+
+// Dates travel as RFC 3339 text. An empty string parses to the zero time so
+// the services can report a missing value themselves.
+
+// parseStartDate reads an auction start. The offset is required so the
+// start is one instant wherever the seller is; it is kept in UTC.
+func parseStartDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, errors.New("start_date must be an RFC 3339 timestamp with an offset, e.g. 2026-05-01T09:00:00+02:00")
+	}
+	return t.UTC(), nil
+}
+
+// parseHarvestDate reads a calendar day as an RFC 3339 full-date. A full
+// timestamp, as lots are written back out, is accepted too and stands for
+// its day at its own offset.
+func parseHarvestDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("harvest_date must be an RFC 3339 date, e.g. 2026-04-20")
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"banana-auction/api/apierror"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/money"
+)
+
+type FXHandler struct {
+	svc fx.Service
+}
+
+func NewFXHandler(svc fx.Service) *FXHandler {
+	return &FXHandler{svc: svc}
+}
+
+// List shows the whole rate table. It is public, like the indicative prices
+// computed from it.
+func (h *FXHandler) List(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.svc.ListRates(r.Context())
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+	if rates == nil {
+		rates = []fx.Rate{}
+	}
+	json.NewEncoder(w).Encode(rates)
+}
+
+// Set saves a batch of rates sent as JSON.
+func (h *FXHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req []struct {
+		Base  string      `json:"base"`
+		Quote string      `json:"quote"`
+		Rate  json.Number `json:"rate"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, apierror.InvalidBody())
+		return
+	}
+
+	rates := make([]fx.Rate, 0, len(req))
+	for _, e := range req {
+		rate, err := money.ParseRate(e.Rate.String())
+		if err != nil {
+			apierror.Write(w, apierror.InvalidField("rate", fmt.Errorf("invalid rate for %s/%s: %w", e.Base, e.Quote, err)))
+			return
+		}
+		rates = append(rates, fx.Rate{
+			Base:  money.Currency(strings.ToUpper(e.Base)),
+			Quote: money.Currency(strings.ToUpper(e.Quote)),
+			Rate:  rate,
+		})
+	}
+
+	if err := h.svc.SetRates(r.Context(), rates); err != nil {
+		apierror.Write(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]int{"saved": len(rates)})
+}
+
+// Import saves the rates in a CSV request body, all or nothing.
+func (h *FXHandler) Import(w http.ResponseWriter, r *http.Request) {
+	n, err := h.svc.ImportCSV(r.Context(), r.Body)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]int{"saved": n})
+}
+
+func (h *FXHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	// Extract the pair from the path (e.g., /admin/fx-rates/USD/EUR)
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) != 4 {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_path", "Invalid URL format. Use /admin/fx-rates/{base}/{quote}"))
+		return
+	}
+	base := money.Currency(strings.ToUpper(pathParts[2]))
+	quote := money.Currency(strings.ToUpper(pathParts[3]))
+
+	if err := h.svc.DeleteRate(r.Context(), base, quote); err != nil {
+		apierror.Write(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"banana-auction/api/apierror"
+	"banana-auction/api/middlewares"
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/event"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/infrastructure/pubsub"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	liveWriteTimeout = 10 * time.Second
+	livePingInterval = 30 * time.Second
+)
+
+var upgrader = websocket.Upgrader{CheckOrigin: checkOrigin}
+
+// checkOrigin accepts clients that send no Origin (not a browser), pages from
+// the API's own host and the origins CorsMiddleware allows.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return middlewares.AllowedOrigin(origin)
+}
+
+type LiveHandler struct {
+	hub        *pubsub.Hub
+	auctionSvc auction.Service
+	lotSvc     lot.Service
+	bidSvc     bid.Service
+}
+
+func NewLiveHandler(hub *pubsub.Hub, auctionSvc auction.Service, lotSvc lot.Service, bidSvc bid.Service) *LiveHandler {
+	return &LiveHandler{hub: hub, auctionSvc: auctionSvc, lotSvc: lotSvc, bidSvc: bidSvc}
+}
+
+// liveMessage is what a client receives. BuyerID is only filled in for the
+// seller and for the buyer the event is about.
+type liveMessage struct {
+	Type       string         `json:"type"`
+	AuctionID  int            `json:"auction_id"`
+	Status     string         `json:"status,omitempty"`
+	BidID      int            `json:"bid_id,omitempty"`
+	BuyerID    int            `json:"buyer_id,omitempty"`
+	PricePerKG money.Amount   `json:"price_per_kg,omitzero"`
+	Currency   money.Currency `json:"currency,omitempty"`
+	QuantityKG int            `json:"quantity_kg,omitempty"`
+	ReserveMet *bool          `json:"reserve_met,omitempty"`
+	EndsAt     *time.Time     `json:"ends_at,omitempty"`
+	Reason     string         `json:"reason,omitempty"`
+	At         time.Time      `json:"at"`
+}
+
+func (h *LiveHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	// Extract auctionID from the path (e.g., /auctions/7/live)
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 3 || pathParts[len(pathParts)-1] != "live" {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_path", "Invalid URL format. Use /auctions/{auctionID}/live"))
+		return
+	}
+	auctionID, err := strconv.Atoi(pathParts[len(pathParts)-2])
+	if err != nil {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid auction ID"))
+		return
+	}
+
+	userID, err := middlewares.GetUserID(r)
+	if err != nil {
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+		return
+	}
+
+	a, err := h.auctionSvc.GetAuction(r.Context(), auctionID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	l, err := h.lotSvc.GetLot(r.Context(), a.LotID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+	isSeller := l.SellerID == userID
+
+	// Subscribe before reading the snapshot so no bid falls in between.
+	sub := h.hub.Subscribe(auctionID)
+	defer sub.Close()
+
+	// Sealed auctions never reveal the standing bid.
+	var highest *bid.Bid
+	if !a.Type.Sealed() {
+		highest, err = h.bidSvc.GetHighestBid(r.Context(), auctionID)
+		if err != nil {
+			apierror.Write(w, err)
+			return
+		}
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade already replied to the client
+	}
+	defer conn.Close()
+
+	snapshot := liveMessage{
+		Type:      "snapshot",
+		AuctionID: auctionID,
+		Status:    string(a.Status),
+		Reason:    a.CancelReason,
+		At:        time.Now(),
+	}
+	end := a.EndTime()
+	snapshot.EndsAt = &end
+	snapshot.ReserveMet = reserveMet(a, highest)
+	if a.Type == auction.TypeDutch && highest == nil {
+		// Nobody has accepted yet, so show the clock price.
+		snapshot.PricePerKG = a.PriceAt(snapshot.At)
+		snapshot.Currency = a.Currency
+	}
+	if highest != nil {
+		snapshot.BidID = highest.ID
+		snapshot.PricePerKG = highest.BidPricePerKG
+		snapshot.Currency = a.Currency
+		snapshot.QuantityKG = highest.QuantityKG
+		if isSeller || highest.BuyerID == userID {
+			snapshot.BuyerID = highest.BuyerID
+		}
+	}
+	if err := writeLive(conn, snapshot); err != nil {
+		return
+	}
+
+	// Drain client frames so close and pong control messages are processed.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(livePingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-r.Context().Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server going away"),
+				time.Now().Add(liveWriteTimeout))
+			return
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case e, ok := <-sub.C:
+			if !ok {
+				// The hub dropped us for falling behind.
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too slow, please reconnect"),
+					time.Now().Add(liveWriteTimeout))
+				return
+			}
+			if e.Type == event.Outbid && e.BuyerID != userID && !isSeller {
+				continue
+			}
+			msg := liveMessage{
+				Type:       string(e.Type),
+				AuctionID:  e.AuctionID,
+				BidID:      e.BidID,
+				PricePerKG: e.PricePerKG,
+				Currency:   e.PricePerKG.Currency(),
+				QuantityKG: e.QuantityKG,
+				EndsAt:     e.EndsAt,
+				Reason:     e.Reason,
+				At:         e.At,
+			}
+			if isSeller || e.BuyerID == userID {
+				msg.BuyerID = e.BuyerID
+			}
+			if e.Type == event.NewHighBid {
+				msg.ReserveMet = reserveMet(a, &bid.Bid{BidPricePerKG: e.PricePerKG})
+			}
+			if err := writeLive(conn, msg); err != nil {
+				return
+			}
+			if e.Type == event.AuctionClosed || e.Type == event.AuctionCancelled {
+				text := "auction closed"
+				if e.Type == event.AuctionCancelled {
+					text = "auction cancelled"
+				}
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, text),
+					time.Now().Add(liveWriteTimeout))
+				return
+			}
+		}
+	}
+}
+
+func writeLive(conn *websocket.Conn, msg liveMessage) error {
+	conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+	return conn.WriteJSON(msg)
+}
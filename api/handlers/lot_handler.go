@@ -7,24 +7,23 @@ import (
 	"strconv"
 	"strings"
 
+	"banana-auction/api/apierror"
 	"banana-auction/internal/domain/lot"
-	"banana-auction/internal/domain/user"
 	"banana-auction/api/middlewares"
 )
 
 type LotHandler struct {
-	svc    lot.Service
-	userSvc user.Service
+	svc lot.Service
 }
 
-func NewLotHandler(svc lot.Service, userSvc user.Service) *LotHandler {
-	return &LotHandler{svc: svc, userSvc: userSvc}
+func NewLotHandler(svc lot.Service) *LotHandler {
+	return &LotHandler{svc: svc}
 }
 
 func (h *LotHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userID, err := middlewares.GetUserID(r)
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
@@ -35,13 +34,19 @@ func (h *LotHandler) Create(w http.ResponseWriter, r *http.Request) {
 		TotalWeightKG  int    `json:"total_weight_kg"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, apierror.InvalidBody())
 		return
 	}
 
-	id, err := h.svc.CreateLot(userID, req.Cultivar, req.PlantedCountry, req.HarvestDate, req.TotalWeightKG)
+	harvestDate, err := parseHarvestDate(req.HarvestDate)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, apierror.InvalidField("harvest_date", err))
+		return
+	}
+
+	id, err := h.svc.CreateLot(r.Context(), userID, req.Cultivar, req.PlantedCountry, harvestDate, req.TotalWeightKG)
+	if err != nil {
+		apierror.Write(w, err)
 		return
 	}
 
@@ -53,13 +58,13 @@ func (h *LotHandler) Update(w http.ResponseWriter, r *http.Request) {
 	idStr := pathParts[len(pathParts)-1]
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid lot ID", http.StatusBadRequest)
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid lot ID"))
 		return
 	}
 
 	userID, err := middlewares.GetUserID(r)
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
@@ -67,12 +72,18 @@ func (h *LotHandler) Update(w http.ResponseWriter, r *http.Request) {
 		HarvestDate string `json:"harvest_date"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, apierror.InvalidBody())
+		return
+	}
+
+	harvestDate, err := parseHarvestDate(req.HarvestDate)
+	if err != nil {
+		apierror.Write(w, apierror.InvalidField("harvest_date", err))
 		return
 	}
 
-	if err := h.svc.UpdateLot(id, userID, req.HarvestDate); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := h.svc.UpdateLot(r.Context(), id, userID, harvestDate); err != nil {
+		apierror.Write(w, err)
 		return
 	}
 
@@ -84,18 +95,18 @@ func (h *LotHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := pathParts[len(pathParts)-1]
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid lot ID", http.StatusBadRequest)
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid lot ID"))
 		return
 	}
 
 	userID, err := middlewares.GetUserID(r)
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
-	if err := h.svc.DeleteLot(id, userID); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := h.svc.DeleteLot(r.Context(), id, userID); err != nil {
+		apierror.Write(w, err)
 		return
 	}
 
@@ -105,28 +116,18 @@ func (h *LotHandler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *LotHandler) List(w http.ResponseWriter, r *http.Request) {
 	userID, err := middlewares.GetUserID(r)
 	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
-	// Fetch the user to check their role
-	user, err := h.userSvc.GetUser(userID)
+	// List only the caller's own lots
+	lots, err := h.svc.ListSellerLots(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "User not found", http.StatusInternalServerError)
-		return
-	}
-
-	// Check if the user is a seller
-	if user.Role != "seller" {
-		http.Error(w, "Only sellers can list lots", http.StatusForbidden)
+		apierror.Write(w, err)
 		return
 	}
-
-	// List all lots
-	lots, err := h.svc.ListLots()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	if lots == nil {
+		lots = []lot.Summary{}
 	}
 
 	json.NewEncoder(w).Encode(lots)
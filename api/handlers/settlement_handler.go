@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"banana-auction/api/apierror"
+	"banana-auction/api/middlewares"
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/settlement"
+)
+
+type SettlementHandler struct {
+	svc        settlement.Service
+	auctionSvc auction.Service
+	lotSvc     lot.Service
+}
+
+func NewSettlementHandler(svc settlement.Service, auctionSvc auction.Service, lotSvc lot.Service) *SettlementHandler {
+	return &SettlementHandler{svc: svc, auctionSvc: auctionSvc, lotSvc: lotSvc}
+}
+
+func (h *SettlementHandler) GetResult(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apierror.Write(w, apierror.New(http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed"))
+		return
+	}
+
+	// Extract auctionID from the path (e.g., /auctions/7/result)
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 3 || pathParts[len(pathParts)-1] != "result" {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_path", "Invalid URL format. Use /auctions/{auctionID}/result"))
+		return
+	}
+	auctionID, err := strconv.Atoi(pathParts[len(pathParts)-2])
+	if err != nil {
+		apierror.Write(w, apierror.New(http.StatusBadRequest, "invalid_id", "Invalid auction ID"))
+		return
+	}
+
+	userID, err := middlewares.GetUserID(r)
+	if err != nil {
+		apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+		return
+	}
+
+	a, err := h.auctionSvc.GetAuction(r.Context(), auctionID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	l, err := h.lotSvc.GetLot(r.Context(), a.LotID)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	result, err := h.svc.GetResult(r.Context(), auctionID)
+	if errors.Is(err, settlement.ErrNotFound) {
+		apierror.Write(w, apierror.New(http.StatusNotFound, "result_not_available", "Auction result is not available yet"))
+		return
+	}
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	// Only the seller and the winning buyers may see the result. A buyer of
+	// a multi-unit lot only sees their own allocation, with the totals worked
+	// out from it. The commission is on the whole lot and is left out.
+	isWinner := result.WinnerID != nil && *result.WinnerID == userID
+	if l.SellerID != userID && result.Allocations != nil {
+		var own []settlement.Allocation
+		result.TotalWeightKG, result.TotalAmount = 0, money.Zero(result.Currency)
+		for _, a := range result.Allocations {
+			if a.BuyerID == userID {
+				own = append(own, a)
+				result.TotalWeightKG += a.QuantityKG
+				if result.TotalAmount, err = result.TotalAmount.Add(a.Amount); err != nil {
+					apierror.Write(w, err)
+					return
+				}
+			}
+		}
+		isWinner = len(own) > 0
+		result.Allocations = own
+		result.Commission = money.Zero(result.Currency)
+	}
+	if l.SellerID != userID && !isWinner {
+		apierror.Write(w, apierror.Forbidden("Only the seller or a winning buyer can view this result"))
+		return
+	}
+
+	json.NewEncoder(w).Encode(result)
+}
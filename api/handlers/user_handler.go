@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"banana-auction/api/apierror"
 	"banana-auction/internal/domain/user"
 )
 
@@ -23,13 +24,13 @@ func (h *UserHandler) Signup(w http.ResponseWriter, r *http.Request) {
 		Role     string `json:"role"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, apierror.InvalidBody())
 		return
 	}
 
-	id, err := h.svc.Register(req.Username, req.Password, req.Name, req.Role)
+	id, err := h.svc.Register(r.Context(), req.Username, req.Password, req.Name, req.Role)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, err)
 		return
 	}
 
@@ -42,15 +43,58 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Password string `json:"password"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, apierror.InvalidBody())
 		return
 	}
 
-	token, err := h.svc.Login(req.Username, req.Password)
+	tokens, err := h.svc.Login(r.Context(), req.Username, req.Password)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		apierror.Write(w, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"token": token})
+	writeTokens(w, tokens)
+}
+
+func (h *UserHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		apierror.Write(w, apierror.InvalidBody())
+		return
+	}
+
+	tokens, err := h.svc.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	writeTokens(w, tokens)
+}
+
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		apierror.Write(w, apierror.InvalidBody())
+		return
+	}
+
+	if err := h.svc.Logout(r.Context(), req.RefreshToken); err != nil {
+		apierror.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeTokens(w http.ResponseWriter, tokens user.Tokens) {
+	json.NewEncoder(w).Encode(map[string]any{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    int(tokens.ExpiresIn.Seconds()),
+	})
 }
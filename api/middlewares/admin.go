@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"banana-auction/api/apierror"
+)
+
+// RequireAdminKey only lets requests through that send key in the
+// X-Admin-Key header. Admins are operators rather than marketplace users, so
+// they have no account or JWT. With an empty key the admin API is disabled.
+func RequireAdminKey(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key == "" {
+				apierror.Write(w, apierror.New(http.StatusForbidden, "admin_api_disabled", "Admin API is disabled"))
+				return
+			}
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Key")), []byte(key)) != 1 {
+				apierror.Write(w, apierror.New(http.StatusUnauthorized, "invalid_admin_key", "Invalid admin key"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
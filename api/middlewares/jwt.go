@@ -6,24 +6,33 @@ import (
 	"net/http"
 	"strings"
 
+	"banana-auction/api/apierror"
 	"banana-auction/config"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var userIDKey = "userID"
+var (
+	userIDKey = "userID"
+	roleKey   = "role"
+)
 
 func JwtAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
+		// Browsers can't set headers on WebSocket handshakes, so those may
+		// pass the token as ?access_token= instead.
+		if authHeader == "" && isWebSocketUpgrade(r) && r.URL.Query().Get("access_token") != "" {
+			authHeader = "Bearer " + r.URL.Query().Get("access_token")
+		}
 		if authHeader == "" {
-			http.Error(w, "Missing Authorization header", http.StatusUnauthorized)
+			apierror.Write(w, apierror.Unauthorized("Missing Authorization header"))
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid Authorization header", http.StatusUnauthorized)
+			apierror.Write(w, apierror.Unauthorized("Invalid Authorization header"))
 			return
 		}
 
@@ -32,27 +41,39 @@ func JwtAuthMiddleware(next http.Handler) http.Handler {
 			return []byte(config.GetConfig().JwtSecretKey), nil
 		})
 		if err != nil || !token.Valid {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			apierror.Write(w, apierror.Unauthorized("Invalid token"))
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			http.Error(w, "Invalid claims", http.StatusUnauthorized)
+			apierror.Write(w, apierror.Unauthorized("Invalid claims"))
 			return
 		}
 
 		userIDFloat, ok := claims["user_id"].(float64)
 		if !ok {
-			http.Error(w, "Invalid user_id", http.StatusUnauthorized)
+			apierror.Write(w, apierror.Unauthorized("Invalid user_id"))
 			return
 		}
 
-		r = r.WithContext(context.WithValue(r.Context(), userIDKey, int(userIDFloat)))
+		role, ok := claims["role"].(string)
+		if !ok || role == "" {
+			apierror.Write(w, apierror.Unauthorized("Invalid role"))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDKey, int(userIDFloat))
+		ctx = context.WithValue(ctx, roleKey, role)
+		r = r.WithContext(ctx)
 		next.ServeHTTP(w, r)
 	})
 }
 
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
 func GetUserID(r *http.Request) (int, error) {
 	userID, ok := r.Context().Value(userIDKey).(int)
 	if !ok {
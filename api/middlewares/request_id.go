@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"banana-auction/api/apierror"
+)
+
+// RequestID tags every response with an X-Request-ID header. A proxy's ID is
+// kept if it sends one; otherwise a random one is made up.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(apierror.RequestIDHeader)
+		if id == "" || len(id) > 64 {
+			id = newRequestID()
+		}
+		w.Header().Set(apierror.RequestIDHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"banana-auction/api/apierror"
+)
+
+// RequireRole only lets requests through whose JWT role is one of roles. It
+// must run inside JwtAuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, err := GetRole(r)
+			if err != nil {
+				apierror.Write(w, apierror.Unauthorized("Unauthorized"))
+				return
+			}
+			if !slices.Contains(roles, role) {
+				apierror.Write(w, apierror.Forbidden("Forbidden for role "+role))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func GetRole(r *http.Request) (string, error) {
+	role, ok := r.Context().Value(roleKey).(string)
+	if !ok {
+		return "", errors.New("role not found in request")
+	}
+	return role, nil
+}
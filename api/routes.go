@@ -6,52 +6,83 @@ import (
 
 	"banana-auction/api/handlers"
 	"banana-auction/api/middlewares"
+	"banana-auction/config"
 	"banana-auction/internal/domain/auction"
 	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/catalog"
+	"banana-auction/internal/domain/fx"
 	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/settlement"
 	"banana-auction/internal/domain/user"
-	"banana-auction/internal/infrastructure/persistence/postgres"
+	"banana-auction/internal/infrastructure/pubsub"
 )
 
-func SetupRoutes() http.Handler {
-	mux := http.NewServeMux()
-
-	userSvc := user.NewService(postgres.NewUserRepo(postgres.GetDB()))
-	userHandler := handlers.NewUserHandler(userSvc)
-
-	lotSvc := lot.NewService(postgres.NewLotRepo(postgres.GetDB()))
-	lotHandler := handlers.NewLotHandler(lotSvc, userSvc)
+// Services are the domain services behind the routes. They are built once
+// at startup and shared with the scheduler.
+type Services struct {
+	Users       user.Service
+	Lots        lot.Service
+	Auctions    auction.Service
+	Bids        bid.Service
+	Settlements settlement.Service
+	Catalog     catalog.Service
+	FX          fx.Service
+}
 
-	auctionSvc := auction.NewService(postgres.NewAuctionRepo(postgres.GetDB()))
-	bidSvc := bid.NewService(postgres.NewBidRepo(postgres.GetDB()))
-	auctionHandler := handlers.NewAuctionHandler(auctionSvc, lotSvc, bidSvc)
+func SetupRoutes(svc Services, hub *pubsub.Hub) http.Handler {
+	mux := http.NewServeMux()
 
-	bidHandler := handlers.NewBidHandler(bidSvc)
+	userHandler := handlers.NewUserHandler(svc.Users)
+	lotHandler := handlers.NewLotHandler(svc.Lots)
+	auctionHandler := handlers.NewAuctionHandler(svc.Auctions, svc.Lots, svc.Bids, config.GetConfig().Currency)
+	bidHandler := handlers.NewBidHandler(svc.Bids, svc.Auctions, svc.Lots)
+	fxHandler := handlers.NewFXHandler(svc.FX)
+	settlementHandler := handlers.NewSettlementHandler(svc.Settlements, svc.Auctions, svc.Lots)
+	liveHandler := handlers.NewLiveHandler(hub, svc.Auctions, svc.Lots, svc.Bids)
+	catalogHandler := handlers.NewCatalogHandler(svc.Catalog, config.GetConfig().Currency)
 
 	// Public routes
 	mux.Handle("POST /signup",http.HandlerFunc(userHandler.Signup))
 	mux.Handle("POST /login",http.HandlerFunc(userHandler.Login))
+	mux.Handle("POST /token/refresh", http.HandlerFunc(userHandler.Refresh))
+	mux.Handle("POST /logout", http.HandlerFunc(userHandler.Logout))
+	mux.Handle("GET /auctions", http.HandlerFunc(catalogHandler.Browse))
+	mux.Handle("GET /fx-rates", http.HandlerFunc(fxHandler.List))
+
+	// Admin routes, authenticated by ADMIN_API_KEY instead of a JWT
+	adminOnly := middlewares.RequireAdminKey(config.GetConfig().AdminAPIKey)
+	mux.Handle("PUT /admin/fx-rates", adminOnly(http.HandlerFunc(fxHandler.Set)))
+	mux.Handle("POST /admin/fx-rates/import", adminOnly(http.HandlerFunc(fxHandler.Import)))
+	mux.Handle("DELETE /admin/fx-rates/{base}/{quote}", adminOnly(http.HandlerFunc(fxHandler.Delete)))
+
+	// Role requirements
+	sellerOnly := middlewares.RequireRole(user.RoleSeller)
+	buyerOnly := middlewares.RequireRole(user.RoleBuyer)
 
 	// Protected routes
 	protectedMux := http.NewServeMux()
-	protectedMux.HandleFunc("/lots", lotHandler.Create)
-	protectedMux.HandleFunc("/lots/{id}", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPatch {
-			lotHandler.Update(w, r)
-		} else if r.Method == http.MethodDelete {
-			lotHandler.Delete(w, r)
-		} else {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-	protectedMux.HandleFunc("/auctions", auctionHandler.Create)
-	protectedMux.HandleFunc("/auctions/{id}", auctionHandler.GetAuction)
-	protectedMux.HandleFunc("/auctions/{id}/bids", auctionHandler.ListBids)
-	protectedMux.HandleFunc("/auctions/{id}/bids/", bidHandler.PlaceBid) 
+	protectedMux.Handle("POST /lots", sellerOnly(http.HandlerFunc(lotHandler.Create)))
+	protectedMux.Handle("PATCH /lots/{id}", sellerOnly(http.HandlerFunc(lotHandler.Update)))
+	protectedMux.Handle("DELETE /lots/{id}", sellerOnly(http.HandlerFunc(lotHandler.Delete)))
+	protectedMux.Handle("GET /me/lots", sellerOnly(http.HandlerFunc(lotHandler.List)))
+	protectedMux.Handle("GET /me/auctions", sellerOnly(http.HandlerFunc(auctionHandler.ListMine)))
+	protectedMux.Handle("POST /auctions", sellerOnly(http.HandlerFunc(auctionHandler.Create)))
+	protectedMux.HandleFunc("GET /auctions/{id}", auctionHandler.GetAuction)
+	protectedMux.Handle("PATCH /auctions/{id}", sellerOnly(http.HandlerFunc(auctionHandler.Update)))
+	protectedMux.Handle("DELETE /auctions/{id}", sellerOnly(http.HandlerFunc(auctionHandler.Cancel)))
+	protectedMux.Handle("GET /auctions/{id}/bids", sellerOnly(http.HandlerFunc(auctionHandler.ListBids)))
+	protectedMux.Handle("GET /me/bids", buyerOnly(http.HandlerFunc(bidHandler.ListMine)))
+	protectedMux.Handle("POST /auctions/{id}/bids", buyerOnly(http.HandlerFunc(bidHandler.PlaceBid)))
+	protectedMux.Handle("POST /auctions/{id}/bids/", buyerOnly(http.HandlerFunc(bidHandler.PlaceBid)))
+	protectedMux.Handle("POST /auctions/{id}/proxy-bid", buyerOnly(http.HandlerFunc(bidHandler.PlaceProxyBid)))
+	protectedMux.Handle("GET /auctions/{id}/proxy-bid", buyerOnly(http.HandlerFunc(bidHandler.GetProxyBid)))
+	protectedMux.Handle("POST /auctions/{id}/accept", buyerOnly(http.HandlerFunc(bidHandler.Accept)))
+	protectedMux.HandleFunc("GET /auctions/{id}/result", settlementHandler.GetResult)
+	protectedMux.HandleFunc("GET /auctions/{id}/live", liveHandler.Stream)
 
 	protectedHandler := middlewares.JwtAuthMiddleware(protectedMux)
 
 	mux.Handle("/", protectedHandler)
 
-	return middlewares.CorsMiddleware(mux)
+	return middlewares.RequestID(middlewares.CorsMiddleware(mux))
 }
\ No newline at end of file
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"banana-auction/config"
+	"banana-auction/internal/infrastructure/persistence/postgres"
+	"banana-auction/internal/infrastructure/persistence/sqlite"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// migrationStatus is one line of migrate status for either SQL backend.
+type migrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// migrator is one SQL backend's migration functions.
+type migrator struct {
+	up     func(db *sql.DB) (int, error)
+	down   func(db *sql.DB, steps int) (int, error)
+	status func(db *sql.DB) ([]migrationStatus, error)
+}
+
+// connectMigrator connects to the database cfg.DbDriver names.
+func connectMigrator(cfg *config.Config) (*sql.DB, migrator, error) {
+	switch cfg.DbDriver {
+	case config.DriverPostgres:
+		if err := postgres.Connect(cfg); err != nil {
+			return nil, migrator{}, err
+		}
+		return postgres.GetDB(), migrator{
+			up:   postgres.MigrateUp,
+			down: postgres.MigrateDown,
+			status: func(db *sql.DB) ([]migrationStatus, error) {
+				statuses, err := postgres.MigrationStatuses(db)
+				lines := make([]migrationStatus, len(statuses))
+				for i, s := range statuses {
+					lines[i] = migrationStatus(s)
+				}
+				return lines, err
+			},
+		}, nil
+	case config.DriverSQLite:
+		if err := sqlite.Connect(cfg); err != nil {
+			return nil, migrator{}, err
+		}
+		return sqlite.GetDB(), migrator{
+			up:   sqlite.MigrateUp,
+			down: sqlite.MigrateDown,
+			status: func(db *sql.DB) ([]migrationStatus, error) {
+				statuses, err := sqlite.MigrationStatuses(db)
+				lines := make([]migrationStatus, len(statuses))
+				for i, s := range statuses {
+					lines[i] = migrationStatus(s)
+				}
+				return lines, err
+			},
+		}, nil
+	default:
+		return nil, migrator{}, fmt.Errorf("migrations do not apply to the %s driver", cfg.DbDriver)
+	}
+}
+
+// Migrate runs the migrate subcommand: up applies all pending migrations,
+// down rolls back the latest steps migrations (default 1) and status lists
+// every migration with the time it was applied.
+func Migrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	db, m, err := connectMigrator(config.GetConfig())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := m.up(db)
+		if err != nil {
+			log.Fatalf("Migrate up failed: %v", err)
+		}
+		log.Printf("Applied %d migrations", n)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				log.Fatal("Steps must be a positive number")
+			}
+		}
+		n, err := m.down(db, steps)
+		if err != nil {
+			log.Fatalf("Migrate down failed: %v", err)
+		}
+		log.Printf("Rolled back %d migrations", n)
+	case "status":
+		statuses, err := m.status(db)
+		if err != nil {
+			log.Fatalf("Migrate status failed: %v", err)
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-30s  %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
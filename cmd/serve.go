@@ -1,25 +1,100 @@
 package cmd
 
 import (
-	"banana-auction/config"
 	"banana-auction/api"
-	"banana-auction/internal/infrastructure/persistence/postgres"
+	"banana-auction/config"
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/catalog"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/settlement"
+	"banana-auction/internal/domain/user"
+	"banana-auction/internal/infrastructure/persistence"
+	"banana-auction/internal/infrastructure/pubsub"
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func Serve() {
 	cfg := config.GetConfig()
-	if err := postgres.InitDB(cfg); err != nil {
+	repos, err := persistence.Open(cfg)
+	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
+	// Cancelled on SIGINT/SIGTERM, which stops the scheduler and shuts the
+	// server down.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// Shared by the scheduler and the HTTP handlers so live subscribers see
+	// both bids and closes.
+	hub := pubsub.NewHub(cfg.LiveEventBuffer)
+
+	lotSvc := lot.NewService(repos.Lots, repos.Tx)
+	auctionSvc := auction.NewService(repos.Auctions, repos.Tx, hub)
+	bidSvc := bid.NewService(repos.Bids, hub)
+	fxSvc := fx.NewService(repos.FX, repos.Tx)
+	settlementSvc := settlement.NewService(
+		repos.Settlements,
+		repos.Tx,
+		auctionSvc,
+		lotSvc,
+		bidSvc,
+		fxSvc,
+		cfg.CommissionRate,
+		cfg.Currency,
+	)
+
+	scheduler := auction.NewScheduler(auctionSvc, cfg.SchedulerInterval)
+	scheduler.OnClosed(func(ctx context.Context, a auction.Auction) error {
+		_, err := settlementSvc.SettleAuction(ctx, a.ID)
+		return err
+	})
+	go scheduler.Run(ctx)
+
 	// handler := routes.SetupRoutes()
-	handler := api.SetupRoutes()
+	handler := api.SetupRoutes(api.Services{
+		Users:       user.NewService(repos.Users, repos.Tokens),
+		Lots:        lotSvc,
+		Auctions:    auctionSvc,
+		Bids:        bidSvc,
+		Settlements: settlementSvc,
+		Catalog:     catalog.NewService(repos.Catalog, fxSvc),
+		FX:          fxSvc,
+	}, hub)
+
+	// Requests are not tied to the signal: Shutdown lets the ones in flight
+	// finish. Live streams are hijacked connections that Shutdown does not
+	// wait for, so their context is cancelled once it returns.
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+	server := &http.Server{
+		Addr:        fmt.Sprintf(":%d", cfg.HttpPort),
+		Handler:     handler,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
+	}
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.Printf("Server shutdown: %v", err)
+		}
+		cancelBase()
+	}()
 
 	log.Printf("Starting server on :%d", cfg.HttpPort)
-	if err := http.ListenAndServe(fmt.Sprintf(":%d", cfg.HttpPort), handler); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Server failed: %v", err)
 	}
+	<-shutdown
 }
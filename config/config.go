@@ -4,23 +4,49 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"banana-auction/internal/domain/money"
 
 	"github.com/joho/godotenv"
 )
 
 var configurations *Config
 
+// Storage backends DB_DRIVER can select.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
 type Config struct {
 	Version       string
 	ServiceName   string
 	HttpPort      int
 	JwtSecretKey  string
 	JwtRefreshKey string
+	DbDriver      string
 	DbHost        string
 	DbPort        int
 	DbUser        string
 	DbPassword    string
 	DbName        string
+	SqlitePath    string
+	DBTimeout     time.Duration
+
+	SchedulerInterval time.Duration
+	// Currency is the home currency: what new auctions are priced in unless
+	// the seller picks another, and what settlements record a rate into.
+	Currency        money.Currency
+	CommissionRate  money.Rate
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	LiveEventBuffer int
+	AllowedOrigins  []string
+	// AdminAPIKey unlocks the admin API; empty disables it.
+	AdminAPIKey string
 }
 
 func loadConfig() {
@@ -59,16 +85,105 @@ func loadConfig() {
 		fmt.Println("Jwt refresh key is required!")
 		os.Exit(1)
 	}
+	accessTokenTTL := 15 * time.Minute
+	if v := os.Getenv("ACCESS_TOKEN_TTL_MINUTES"); v != "" {
+		minutes, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || minutes <= 0 {
+			fmt.Println("Access token TTL must be a positive number of minutes")
+			os.Exit(1)
+		}
+		accessTokenTTL = time.Duration(minutes) * time.Minute
+	}
+	refreshTokenTTL := 30 * 24 * time.Hour
+	if v := os.Getenv("REFRESH_TOKEN_TTL_HOURS"); v != "" {
+		hours, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || hours <= 0 {
+			fmt.Println("Refresh token TTL must be a positive number of hours")
+			os.Exit(1)
+		}
+		refreshTokenTTL = time.Duration(hours) * time.Hour
+	}
+	liveEventBuffer := 16
+	if v := os.Getenv("LIVE_EVENT_BUFFER"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			fmt.Println("Live event buffer must be a positive number")
+			os.Exit(1)
+		}
+		liveEventBuffer = int(n)
+	}
+	allowedOrigins := []string{"*"}
+	if v := os.Getenv("ALLOWED_ORIGINS"); v != "" {
+		allowedOrigins = nil
+		for _, o := range strings.Split(v, ",") {
+			if o = strings.TrimSpace(o); o != "" {
+				allowedOrigins = append(allowedOrigins, o)
+			}
+		}
+	}
+	dbDriver := os.Getenv("DB_DRIVER")
+	if dbDriver == "" {
+		dbDriver = DriverPostgres
+	}
+	if dbDriver != DriverPostgres && dbDriver != DriverSQLite && dbDriver != DriverMemory {
+		fmt.Println("DB driver must be postgres, sqlite or memory")
+		os.Exit(1)
+	}
 	dbHost := os.Getenv("DB_HOST")
 	dbPort := os.Getenv("DB_PORT")
-	db_port, err := strconv.ParseInt(dbPort, 10, 64)
-	if err != nil {
-		fmt.Println("DB Port must be a number")
-		os.Exit(1)
+	var db_port int64
+	if dbDriver == DriverPostgres {
+		db_port, err = strconv.ParseInt(dbPort, 10, 64)
+		if err != nil {
+			fmt.Println("DB Port must be a number")
+			os.Exit(1)
+		}
 	}
 	dbUser := os.Getenv("DB_USER")
 	dbPassword := os.Getenv("DB_PASSWORD")
 	dbName := os.Getenv("DB_NAME")
+	sqlitePath := os.Getenv("SQLITE_PATH")
+	if sqlitePath == "" {
+		sqlitePath = "banana-auction.db"
+	}
+	dbTimeout := 5 * time.Second
+	if v := os.Getenv("DB_TIMEOUT_SECONDS"); v != "" {
+		seconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seconds <= 0 {
+			fmt.Println("DB timeout must be a positive number of seconds")
+			os.Exit(1)
+		}
+		dbTimeout = time.Duration(seconds) * time.Second
+	}
+
+	schedulerInterval := 30 * time.Second
+	if v := os.Getenv("SCHEDULER_INTERVAL_SECONDS"); v != "" {
+		seconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seconds <= 0 {
+			fmt.Println("Scheduler interval must be a positive number of seconds")
+			os.Exit(1)
+		}
+		schedulerInterval = time.Duration(seconds) * time.Second
+	}
+
+	currency := money.Currency("USD")
+	if v := os.Getenv("CURRENCY"); v != "" {
+		currency = money.Currency(strings.ToUpper(strings.TrimSpace(v)))
+		if !currency.Valid() {
+			fmt.Println("Currency must be a supported ISO 4217 code such as USD")
+			os.Exit(1)
+		}
+	}
+
+	commissionRate := money.NewRate(50_000)
+	if v := os.Getenv("COMMISSION_RATE"); v != "" {
+		rate, err := money.ParseRate(v)
+		if err != nil || rate.Millionths() < 0 || rate.Millionths() >= 1_000_000 {
+			fmt.Println("Commission rate must be a number between 0 and 1 with at most six decimal places")
+			os.Exit(1)
+		}
+		commissionRate = rate
+	}
 
 	configurations = &Config{
 		Version:       version,
@@ -76,11 +191,23 @@ func loadConfig() {
 		HttpPort:      int(port),
 		JwtSecretKey:  jwtSecretKey,
 		JwtRefreshKey: jwtRefreshKey,
+		DbDriver:      dbDriver,
 		DbHost:        dbHost,
 		DbPort:        int(db_port),
 		DbUser:        dbUser,
 		DbPassword:    dbPassword,
 		DbName:        dbName,
+		SqlitePath:    sqlitePath,
+		DBTimeout:     dbTimeout,
+
+		SchedulerInterval: schedulerInterval,
+		Currency:          currency,
+		CommissionRate:    commissionRate,
+		AccessTokenTTL:    accessTokenTTL,
+		RefreshTokenTTL:   refreshTokenTTL,
+		LiveEventBuffer:   liveEventBuffer,
+		AllowedOrigins:    allowedOrigins,
+		AdminAPIKey:       os.Getenv("ADMIN_API_KEY"),
 	}
 }
 
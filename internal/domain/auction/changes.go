@@ -0,0 +1,66 @@
+package auction
+
+import (
+	"errors"
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
+var (
+	ErrNotEditable          = errors.New("auction can no longer be edited")
+	ErrBiddingStarted       = errors.New("once bidding has started the duration can only be extended")
+	ErrCancelReasonRequired = errors.New("a reason is required to cancel an auction")
+	ErrStartRequired        = errors.New("a start date is required")
+	ErrStartInPast          = errors.New("start date cannot be in the past")
+)
+
+// Changes is a partial edit of an auction. Nil fields are left as they are.
+type Changes struct {
+	StartDate         *time.Time
+	DurationDays      *int
+	InitialPricePerKG *money.Amount
+	ReservePricePerKG *money.Amount
+	BuyNowPricePerKG  *money.Amount
+	Type              *Type
+	SoftClose         *SoftClose
+	Dutch             *Dutch
+	Clearing          *Clearing
+}
+
+// onlyDuration reports whether the duration is the one thing c changes.
+func (c Changes) onlyDuration() bool {
+	return c.DurationDays != nil && c.StartDate == nil && c.InitialPricePerKG == nil &&
+		c.ReservePricePerKG == nil && c.BuyNowPricePerKG == nil && c.Type == nil &&
+		c.SoftClose == nil && c.Dutch == nil && c.Clearing == nil
+}
+
+func (c Changes) apply(a *Auction) {
+	if c.StartDate != nil {
+		a.StartDate = *c.StartDate
+	}
+	if c.DurationDays != nil {
+		a.DurationDays = *c.DurationDays
+	}
+	if c.InitialPricePerKG != nil {
+		a.InitialPricePerKG = *c.InitialPricePerKG
+	}
+	if c.ReservePricePerKG != nil {
+		a.ReservePricePerKG = *c.ReservePricePerKG
+	}
+	if c.BuyNowPricePerKG != nil {
+		a.BuyNowPricePerKG = *c.BuyNowPricePerKG
+	}
+	if c.Type != nil {
+		a.Type = *c.Type
+	}
+	if c.SoftClose != nil {
+		a.SoftClose = *c.SoftClose
+	}
+	if c.Dutch != nil {
+		a.Dutch = *c.Dutch
+	}
+	if c.Clearing != nil {
+		a.Clearing = *c.Clearing
+	}
+}
@@ -1,9 +1,136 @@
 package auction
 
+import (
+	"errors"
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
+var (
+	ErrInvalidDutchFloor     = errors.New("dutch floor price must be above zero and at most the initial price")
+	ErrInvalidDutchDecrement = errors.New("dutch decrement and interval must be greater than zero")
+	ErrNegativeSoftClose     = errors.New("soft close minutes cannot be negative")
+	ErrIncompleteSoftClose   = errors.New("soft close needs both a window and an extension")
+)
+
 type Auction struct {
-	ID                int
-	LotID             int
-	StartDate         string
-	DurationDays      int
-	InitialPricePerKG float64
-}
\ No newline at end of file
+	ID    int
+	LotID int
+	// StartDate is the instant bidding opens, held in UTC.
+	StartDate    time.Time
+	DurationDays int
+	// Currency is what every price in the auction, and every bid on it, is
+	// quoted and settled in.
+	Currency          money.Currency
+	InitialPricePerKG money.Amount
+	// ReservePricePerKG is the seller's hidden minimum; below it the lot
+	// does not sell. Zero means no reserve.
+	ReservePricePerKG money.Amount
+	// BuyNowPricePerKG lets a buyer end the auction at once by paying it.
+	// Zero means no buy-it-now.
+	BuyNowPricePerKG money.Amount
+	Type             Type
+	Status           Status
+	WinningBidID     *int
+	SoftClose        SoftClose
+	Dutch            Dutch
+	// Clearing is only set on multi-unit auctions.
+	Clearing Clearing
+	// ExtensionMinutes is how far soft close has pushed the end out so far.
+	ExtensionMinutes int
+	// CancelReason is the seller's explanation when Status is cancelled.
+	CancelReason string
+}
+
+// SoftClose is the anti-sniping configuration: a bid in the final
+// WindowMinutes pushes the end out by ExtensionMinutes, up to
+// MaxExtensionMinutes in total (0 means no cap). A zero window disables it.
+type SoftClose struct {
+	WindowMinutes       int
+	ExtensionMinutes    int
+	MaxExtensionMinutes int
+}
+
+// ReserveMet reports whether a bid of pricePerKG clears the reserve.
+func (a Auction) ReserveMet(pricePerKG money.Amount) bool {
+	return pricePerKG.Cmp(a.ReservePricePerKG) >= 0
+}
+
+// EndTime is StartDate plus DurationDays plus any soft-close extension.
+// Days are counted in UTC, so they are always 24 hours long: an auction
+// whose window spans a daylight saving change in the seller's time zone
+// still closes at the same UTC time of day it opened.
+func (a Auction) EndTime() time.Time {
+	start := a.StartDate.UTC()
+	return start.AddDate(0, 0, a.DurationDays).Add(time.Duration(a.ExtensionMinutes) * time.Minute)
+}
+
+// SoftCloseExtension returns how many minutes a bid placed at now extends
+// the auction by, or 0 if it lands outside the closing window or the cap
+// has been reached.
+func (a Auction) SoftCloseExtension(now time.Time) int {
+	sc := a.SoftClose
+	if sc.WindowMinutes <= 0 || sc.ExtensionMinutes <= 0 {
+		return 0
+	}
+	end := a.EndTime()
+	if now.Before(end.Add(-time.Duration(sc.WindowMinutes)*time.Minute)) || !now.Before(end) {
+		return 0
+	}
+
+	minutes := sc.ExtensionMinutes
+	if sc.MaxExtensionMinutes > 0 {
+		minutes = min(minutes, sc.MaxExtensionMinutes-a.ExtensionMinutes)
+	}
+	return max(minutes, 0)
+}
+
+// Dutch is the price clock of a dutch auction: starting at
+// InitialPricePerKG, the price drops by DecrementPerKG every
+// DecrementIntervalMinutes until it reaches FloorPricePerKG.
+type Dutch struct {
+	FloorPricePerKG          money.Amount
+	DecrementPerKG           money.Amount
+	DecrementIntervalMinutes int
+}
+
+// PriceAt returns the dutch clock price at now. Before the start it is the
+// initial price; it never goes below the floor.
+func (a Auction) PriceAt(now time.Time) money.Amount {
+	if !now.After(a.StartDate) || a.Dutch.DecrementIntervalMinutes <= 0 {
+		return a.InitialPricePerKG
+	}
+
+	steps := int64(now.Sub(a.StartDate) / (time.Duration(a.Dutch.DecrementIntervalMinutes) * time.Minute))
+	drop, err := a.Dutch.DecrementPerKG.Mul(steps)
+	if err != nil {
+		// The clock has run far past the floor.
+		return a.Dutch.FloorPricePerKG
+	}
+	price, err := a.InitialPricePerKG.Sub(drop)
+	if err != nil {
+		return a.Dutch.FloorPricePerKG
+	}
+	return money.Max(price, a.Dutch.FloorPricePerKG)
+}
+
+func (d Dutch) validate(initialPricePerKG money.Amount) error {
+	if d.FloorPricePerKG.Sign() <= 0 || d.FloorPricePerKG.Cmp(initialPricePerKG) > 0 {
+		return ErrInvalidDutchFloor
+	}
+	if d.DecrementPerKG.Sign() <= 0 || d.DecrementIntervalMinutes <= 0 {
+		return ErrInvalidDutchDecrement
+	}
+	return nil
+}
+
+func (sc SoftClose) validate() error {
+	if sc.WindowMinutes < 0 || sc.ExtensionMinutes < 0 || sc.MaxExtensionMinutes < 0 {
+		return ErrNegativeSoftClose
+	}
+	if (sc.WindowMinutes == 0) != (sc.ExtensionMinutes == 0) {
+		return ErrIncompleteSoftClose
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package auction
+
+import (
+	"testing"
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
+var start = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func usd(minor int64) money.Amount { return money.New(minor, "USD") }
+
+func TestSoftCloseExtension(t *testing.T) {
+	a := Auction{
+		StartDate:    start,
+		DurationDays: 1,
+		SoftClose:    SoftClose{WindowMinutes: 5, ExtensionMinutes: 3, MaxExtensionMinutes: 10},
+	}
+	end := start.AddDate(0, 0, 1)
+	extended := a
+	extended.ExtensionMinutes = 9
+	capped := a
+	capped.ExtensionMinutes = 10
+	uncapped := a
+	uncapped.SoftClose.MaxExtensionMinutes = 0
+	uncapped.ExtensionMinutes = 60
+	disabled := a
+	disabled.SoftClose = SoftClose{}
+	tests := []struct {
+		name string
+		a    Auction
+		now  time.Time
+		want int
+	}{
+		{"before the window", a, end.Add(-6 * time.Minute), 0},
+		{"window opens", a, end.Add(-5 * time.Minute), 3},
+		{"last second", a, end.Add(-time.Second), 3},
+		{"at the end", a, end, 0},
+		{"window moves with the extension", extended, end.Add(8 * time.Minute), 1},
+		{"cap reached", capped, end.Add(9 * time.Minute), 0},
+		{"no cap", uncapped, end.Add(59 * time.Minute), 3},
+		{"disabled", disabled, end.Add(-time.Minute), 0},
+	}
+	for _, tt := range tests {
+		if got := tt.a.SoftCloseExtension(tt.now); got != tt.want {
+			t.Errorf("%s: SoftCloseExtension = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEndTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	tests := []struct {
+		name string
+		a    Auction
+		want time.Time
+	}{
+		{"days", Auction{StartDate: start, DurationDays: 3}, start.AddDate(0, 0, 3)},
+		{"with extension", Auction{StartDate: start, DurationDays: 1, ExtensionMinutes: 7}, start.Add(24*time.Hour + 7*time.Minute)},
+		// 8 March 2026 moves New York to daylight saving time.
+		{"across a DST change", Auction{StartDate: time.Date(2026, 3, 7, 9, 0, 0, 0, ny), DurationDays: 2}, time.Date(2026, 3, 9, 14, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := tt.a.EndTime(); !got.Equal(tt.want) {
+			t.Errorf("%s: EndTime = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPriceAt(t *testing.T) {
+	a := Auction{
+		StartDate:         start,
+		Currency:          "USD",
+		InitialPricePerKG: usd(200),
+		Dutch: Dutch{
+			FloorPricePerKG:          usd(120),
+			DecrementPerKG:           usd(25),
+			DecrementIntervalMinutes: 10,
+		},
+	}
+	huge := a
+	huge.Dutch.DecrementPerKG = usd(9999999999999999)
+	tests := []struct {
+		name string
+		a    Auction
+		now  time.Time
+		want money.Amount
+	}{
+		{"before the start", a, start.Add(-time.Hour), usd(200)},
+		{"at the start", a, start, usd(200)},
+		{"inside the first interval", a, start.Add(9 * time.Minute), usd(200)},
+		{"one step", a, start.Add(10 * time.Minute), usd(175)},
+		{"three steps", a, start.Add(35 * time.Minute), usd(125)},
+		{"stops at the floor", a, start.Add(40 * time.Minute), usd(120)},
+		{"long after the start", a, start.AddDate(1, 0, 0), usd(120)},
+		{"drop too large to compute", huge, start.Add(time.Hour), usd(120)},
+	}
+	for _, tt := range tests {
+		if got := tt.a.PriceAt(tt.now); got != tt.want {
+			t.Errorf("%s: PriceAt = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDutchValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       Dutch
+		wantErr bool
+	}{
+		{"valid", Dutch{usd(100), usd(5), 10}, false},
+		{"floor equals initial", Dutch{usd(200), usd(5), 10}, false},
+		{"floor above initial", Dutch{usd(201), usd(5), 10}, true},
+		{"zero floor", Dutch{usd(0), usd(5), 10}, true},
+		{"zero decrement", Dutch{usd(100), usd(0), 10}, true},
+		{"zero interval", Dutch{usd(100), usd(5), 0}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.d.validate(usd(200)); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validate = %v, want an error: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestReserveMet(t *testing.T) {
+	tests := []struct {
+		reserve, bid money.Amount
+		want         bool
+	}{
+		{usd(150), usd(149), false},
+		{usd(150), usd(150), true},
+		{usd(150), usd(151), true},
+		{money.Zero("USD"), usd(1), true},
+	}
+	for _, tt := range tests {
+		a := Auction{ReservePricePerKG: tt.reserve}
+		if got := a.ReserveMet(tt.bid); got != tt.want {
+			t.Errorf("reserve %v, bid %v: ReserveMet = %v, want %v", tt.reserve, tt.bid, got, tt.want)
+		}
+	}
+}
@@ -1,10 +1,33 @@
 package auction
 
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("auction not found")
+
 type Repository interface {
-	Create(a Auction) (int, error)
-	GetByID(id int) (Auction, error)
-	Update(a Auction) error
-	Delete(id int) error
-	List() ([]Auction, error)
-	ExistsForLot(lotID int) (bool, error)
-}
\ No newline at end of file
+	Create(ctx context.Context, a Auction) (int, error)
+	GetByID(ctx context.Context, id int) (Auction, error)
+	// Update stores the editable fields of a. It reports false when the
+	// auction is no longer in a.Status.
+	Update(ctx context.Context, a Auction) (bool, error)
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context) ([]Auction, error)
+	ListByStatus(ctx context.Context, statuses ...Status) ([]Auction, error)
+	// ListBySeller returns the auctions of the seller's lots with bid
+	// aggregates and time remaining, newest first.
+	ListBySeller(ctx context.Context, sellerID int) ([]Summary, error)
+	ExistsForLot(ctx context.Context, lotID int) (bool, error)
+	// Transition moves the auction from one status to another only if it is
+	// still in from. It reports false when another caller got there first.
+	Transition(ctx context.Context, id int, from, to Status) (bool, error)
+	// Close moves a live auction to closed and records its highest bid as
+	// the winner. It reports false when the auction was not live or its
+	// (possibly extended) end has not been reached.
+	Close(ctx context.Context, id int) (bool, error)
+	// Cancel moves a scheduled or live auction to cancelled with reason. It
+	// reports false when the auction was in any other status.
+	Cancel(ctx context.Context, id int, reason string) (bool, error)
+}
@@ -0,0 +1,100 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// Scheduler opens and closes auctions when their schedule says so, then runs
+// the OnClosed hooks for every closed auction. Every tick it scans all
+// scheduled, live and closed auctions, so work that fell due while the server
+// was down is caught up on the first tick after a restart.
+// Transitions are compare-and-swap in the repository, which makes it safe
+// for several replicas to run a Scheduler against the same database.
+type Scheduler struct {
+	svc      Service
+	interval time.Duration
+	onClosed []func(ctx context.Context, a Auction) error
+}
+
+func NewScheduler(svc Service, interval time.Duration) *Scheduler {
+	return &Scheduler{svc: svc, interval: interval}
+}
+
+// OnClosed registers fn to run for each auction that is in the closed state.
+// fn is retried on every tick until it moves the auction out of closed, so
+// it must be idempotent.
+func (s *Scheduler) OnClosed(fn func(ctx context.Context, a Auction) error) {
+	s.onClosed = append(s.onClosed, fn)
+}
+
+// Run ticks until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx, time.Now()); err != nil {
+			log.Printf("auction scheduler: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick applies every transition that is due at now.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) error {
+	auctions, err := s.svc.ListAuctionsByStatus(ctx, StatusScheduled, StatusLive, StatusClosed)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range auctions {
+		if a.Status == StatusClosed {
+			s.runClosed(ctx, a)
+			continue
+		}
+
+		due := DueTransition(a, now)
+		if due == a.Status {
+			continue
+		}
+
+		// An auction whose whole window passed while we were down still goes
+		// through live so every transition stays legal.
+		if a.Status == StatusScheduled {
+			if err := s.svc.OpenAuction(ctx, a.ID); err != nil && !errors.Is(err, ErrInvalidTransition) {
+				log.Printf("auction scheduler: open auction %d: %v", a.ID, err)
+				continue
+			}
+		}
+		if due == StatusClosed {
+			if err := s.svc.CloseAuction(ctx, a.ID); err != nil {
+				if !errors.Is(err, ErrInvalidTransition) {
+					log.Printf("auction scheduler: close auction %d: %v", a.ID, err)
+				}
+				continue
+			}
+			closed, err := s.svc.GetAuction(ctx, a.ID)
+			if err != nil {
+				log.Printf("auction scheduler: reload auction %d: %v", a.ID, err)
+				continue
+			}
+			s.runClosed(ctx, closed)
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) runClosed(ctx context.Context, a Auction) {
+	for _, fn := range s.onClosed {
+		if err := fn(ctx, a); err != nil {
+			log.Printf("auction scheduler: closed hook for auction %d: %v", a.ID, err)
+		}
+	}
+}
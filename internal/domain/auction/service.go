@@ -1,61 +1,327 @@
 package auction
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"banana-auction/internal/domain/event"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/transaction"
+)
+
+var (
+	ErrAlreadyExists       = errors.New("auction already exists for this lot")
+	ErrDurationTooShort    = errors.New("duration must be at least one day")
+	ErrSoftCloseNotEnglish = errors.New("soft close only applies to english auctions")
+	ErrNegativePrice       = errors.New("reserve and buy-it-now prices cannot be negative")
+	ErrReserveOnDutch      = errors.New("dutch auctions use a floor price instead of a reserve")
+	ErrBuyNowNotEnglish    = errors.New("buy-it-now only applies to english auctions")
+	ErrBuyNowOutOfRange    = errors.New("buy-it-now price must be above the initial price and at least the reserve")
+)
 
 type Service interface {
-	CreateAuction(lotID int, startDate string, durationDays int, initialPricePerKG float64) (int, error)
-	GetAuction(id int) (Auction, error)
-	UpdateAuction(id int, startDate string, durationDays int, initialPricePerKG float64) error
-	DeleteAuction(id int) error
-	ListAuctions() ([]Auction, error)
+	// CreateAuction validates a and stores it as a new scheduled auction.
+	CreateAuction(ctx context.Context, a Auction) (int, error)
+	GetAuction(ctx context.Context, id int) (Auction, error)
+	// UpdateAuction applies c to the auction. Before the start anything may
+	// change; once bidding has begun only the duration can be extended.
+	UpdateAuction(ctx context.Context, id int, c Changes) (Auction, error)
+	DeleteAuction(ctx context.Context, id int) error
+	ListAuctions(ctx context.Context) ([]Auction, error)
+	ListAuctionsByStatus(ctx context.Context, statuses ...Status) ([]Auction, error)
+	ListSellerAuctions(ctx context.Context, sellerID int) ([]Summary, error)
+	OpenAuction(ctx context.Context, id int) error
+	CloseAuction(ctx context.Context, id int) error
+	// CancelAuction withdraws a scheduled or live auction, keeping its bids,
+	// and tells subscribers why.
+	CancelAuction(ctx context.Context, id int, reason string) error
+	SettleAuction(ctx context.Context, id int) error
+	MarkUnsold(ctx context.Context, id int) error
 }
 
 type service struct {
-	repo Repository
+	repo   Repository
+	tx     transaction.Manager
+	events event.Publisher
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, tx transaction.Manager, events event.Publisher) Service {
+	return &service{repo: repo, tx: tx, events: events}
 }
 
-func (s *service) CreateAuction(lotID int, startDate string, durationDays int, initialPricePerKG float64) (int, error) {
-	exists, err := s.repo.ExistsForLot(lotID)
-	if err != nil {
+func (s *service) CreateAuction(ctx context.Context, a Auction) (int, error) {
+	a.Status = StatusScheduled
+	a.WinningBidID = nil
+	a.ExtensionMinutes = 0
+	a.CancelReason = ""
+	if err := normalize(&a, time.Now()); err != nil {
 		return 0, err
 	}
-	if exists {
-		return 0, errors.New("auction already exists for this lot")
+
+	// Serializable so two requests for the same lot can't both pass the
+	// existence check.
+	var id int
+	err := s.tx.Do(ctx, transaction.Serializable, func(ctx context.Context) error {
+		exists, err := s.repo.ExistsForLot(ctx, a.LotID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrAlreadyExists
+		}
+		id, err = s.repo.Create(ctx, a)
+		return err
+	})
+	return id, err
+}
+
+// startSkew is how far in the past a new start may be, so a client whose
+// clock runs a little behind can still ask for "now".
+const startSkew = time.Minute
+
+// normalize fills in defaults for a new or edited auction and checks its
+// settings against each other.
+func normalize(a *Auction, now time.Time) error {
+	if a.StartDate.IsZero() {
+		return ErrStartRequired
+	}
+	// Stores keep different sub-second precision; whole seconds read back
+	// the same from all of them.
+	a.StartDate = a.StartDate.UTC().Truncate(time.Second)
+	if a.StartDate.Before(now.Add(-startSkew)) {
+		return ErrStartInPast
+	}
+	if a.DurationDays <= 0 {
+		return ErrDurationTooShort
+	}
+	if !a.Currency.Valid() {
+		return ErrUnknownCurrency
+	}
+	if a.Type == "" {
+		a.Type = TypeEnglish
+	}
+	if !a.Type.Valid() {
+		return ErrUnknownType
+	}
+	if err := a.SoftClose.validate(); err != nil {
+		return err
+	}
+	if a.Type != TypeEnglish && a.SoftClose.WindowMinutes > 0 {
+		return ErrSoftCloseNotEnglish
+	}
+	if a.Type == TypeDutch {
+		if err := a.Dutch.validate(a.InitialPricePerKG); err != nil {
+			return err
+		}
+	} else {
+		a.Dutch = Dutch{}
+	}
+	if a.Type == TypeMultiUnit {
+		if a.Clearing == "" {
+			a.Clearing = ClearingUniform
+		}
+		if !a.Clearing.Valid() {
+			return ErrUnknownClearing
+		}
+	} else {
+		a.Clearing = ""
+	}
+	return validatePrices(a)
+}
+
+// validatePrices checks every price is in the auction's currency, giving
+// unset ones that currency so they read back the same from every store, and
+// the reserve and buy-it-now against the format.
+// Dutch auctions use their floor instead of a reserve, and only english
+// auctions can be bought outright.
+func validatePrices(a *Auction) error {
+	for _, p := range []*money.Amount{&a.InitialPricePerKG, &a.ReservePricePerKG, &a.BuyNowPricePerKG, &a.Dutch.FloorPricePerKG, &a.Dutch.DecrementPerKG} {
+		if !p.IsZero() && p.Currency() != a.Currency {
+			return ErrCurrencyMismatch
+		}
+		*p = p.In(a.Currency)
+	}
+	if a.ReservePricePerKG.Sign() < 0 || a.BuyNowPricePerKG.Sign() < 0 {
+		return ErrNegativePrice
+	}
+	if a.ReservePricePerKG.Sign() > 0 && a.Type == TypeDutch {
+		return ErrReserveOnDutch
+	}
+	if a.BuyNowPricePerKG.IsZero() {
+		return nil
+	}
+	if a.Type != TypeEnglish {
+		return ErrBuyNowNotEnglish
+	}
+	if a.BuyNowPricePerKG.Cmp(a.InitialPricePerKG) <= 0 || a.BuyNowPricePerKG.Cmp(a.ReservePricePerKG) < 0 {
+		return ErrBuyNowOutOfRange
+	}
+	return nil
+}
+
+func (s *service) GetAuction(ctx context.Context, id int) (Auction, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *service) UpdateAuction(ctx context.Context, id int, c Changes) (Auction, error) {
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return Auction{}, err
+	}
+	if a.Status != StatusScheduled && a.Status != StatusLive {
+		return Auction{}, ErrNotEditable
+	}
+
+	now := time.Now()
+	if !now.Before(a.EndTime()) {
+		// Due to close; extending now would reopen it.
+		return Auction{}, ErrNotEditable
+	}
+	if a.Status == StatusLive || !now.Before(a.StartDate) {
+		// Bidders have committed against the current terms.
+		if !c.onlyDuration() || *c.DurationDays < a.DurationDays {
+			return Auction{}, ErrBiddingStarted
+		}
+		a.DurationDays = *c.DurationDays
+	} else {
+		c.apply(&a)
+		if err := normalize(&a, now); err != nil {
+			return Auction{}, err
+		}
 	}
 
-	a := Auction{
-		LotID:             lotID,
-		StartDate:         startDate,
-		DurationDays:      durationDays,
-		InitialPricePerKG: initialPricePerKG,
+	ok, err := s.repo.Update(ctx, a)
+	if err != nil {
+		return Auction{}, err
+	}
+	if !ok {
+		// The scheduler moved it on between our read and write.
+		return Auction{}, ErrNotEditable
 	}
+	return a, nil
+}
+
+func (s *service) DeleteAuction(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *service) ListAuctions(ctx context.Context) ([]Auction, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *service) ListAuctionsByStatus(ctx context.Context, statuses ...Status) ([]Auction, error) {
+	return s.repo.ListByStatus(ctx, statuses...)
+}
 
-	return s.repo.Create(a)
+func (s *service) ListSellerAuctions(ctx context.Context, sellerID int) ([]Summary, error) {
+	return s.repo.ListBySeller(ctx, sellerID)
 }
 
-func (s *service) GetAuction(id int) (Auction, error) {
-	return s.repo.GetByID(id)
+func (s *service) OpenAuction(ctx context.Context, id int) error {
+	return s.transition(ctx, id, StatusLive)
+}
+
+func (s *service) CloseAuction(ctx context.Context, id int) error {
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !a.Status.CanTransitionTo(StatusClosed) {
+		return ErrInvalidTransition
+	}
+	ok, err := s.repo.Close(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTransition
+	}
+
+	closed, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	e := event.Event{Type: event.AuctionClosed, AuctionID: id, At: time.Now()}
+	if closed.WinningBidID != nil {
+		e.BidID = *closed.WinningBidID
+	}
+	s.events.Publish(e)
+	return nil
 }
 
-func (s *service) UpdateAuction(id int, startDate string, durationDays int, initialPricePerKG float64) error {
-	a, err := s.repo.GetByID(id)
+func (s *service) CancelAuction(ctx context.Context, id int, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrCancelReasonRequired
+	}
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !a.Status.CanTransitionTo(StatusCancelled) {
+		return ErrInvalidTransition
+	}
+	ok, err := s.repo.Cancel(ctx, id, reason)
 	if err != nil {
 		return err
 	}
-	a.StartDate = startDate
-	a.DurationDays = durationDays
-	a.InitialPricePerKG = initialPricePerKG
-	return s.repo.Update(a)
+	if !ok {
+		return ErrInvalidTransition
+	}
+
+	s.events.Publish(event.Event{
+		Type:      event.AuctionCancelled,
+		AuctionID: id,
+		Reason:    reason,
+		At:        time.Now(),
+	})
+	return nil
+}
+
+func (s *service) SettleAuction(ctx context.Context, id int) error {
+	return s.transition(ctx, id, StatusSettled)
+}
+
+func (s *service) MarkUnsold(ctx context.Context, id int) error {
+	return s.transition(ctx, id, StatusUnsold)
 }
 
-func (s *service) DeleteAuction(id int) error {
-	return s.repo.Delete(id)
+func (s *service) transition(ctx context.Context, id int, to Status) error {
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !a.Status.CanTransitionTo(to) {
+		return ErrInvalidTransition
+	}
+	ok, err := s.repo.Transition(ctx, id, a.Status, to)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTransition
+	}
+	return nil
 }
 
-func (s *service) ListAuctions() ([]Auction, error) {
-	return s.repo.List()
+// DueTransition returns the status an auction should be in at now according
+// to its schedule, or its current status if nothing is due.
+func DueTransition(a Auction, now time.Time) Status {
+	end := a.EndTime()
+	switch a.Status {
+	case StatusScheduled:
+		if !now.Before(end) {
+			return StatusClosed
+		}
+		if !now.Before(a.StartDate) {
+			return StatusLive
+		}
+	case StatusLive:
+		if !now.Before(end) {
+			return StatusClosed
+		}
+	}
+	return a.Status
 }
@@ -0,0 +1,43 @@
+package auction
+
+import "errors"
+
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled"
+	StatusLive      Status = "live"
+	StatusClosed    Status = "closed"
+	StatusCancelled Status = "cancelled"
+	StatusSettled   Status = "settled"
+	StatusUnsold    Status = "unsold"
+)
+
+var ErrInvalidTransition = errors.New("invalid auction status transition")
+
+// transitions lists the legal next states for each status. Cancelled,
+// settled and unsold are terminal.
+var transitions = map[Status][]Status{
+	StatusScheduled: {StatusLive, StatusCancelled},
+	StatusLive:      {StatusClosed, StatusCancelled},
+	StatusClosed:    {StatusSettled, StatusUnsold},
+}
+
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, t := range transitions[s] {
+		if t == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Ended reports whether bidding is over for good.
+func (s Status) Ended() bool {
+	return s == StatusClosed || s.Terminal()
+}
+
+// Terminal reports whether no further bids or transitions are possible.
+func (s Status) Terminal() bool {
+	return len(transitions[s]) == 0
+}
@@ -0,0 +1,39 @@
+package auction
+
+import "testing"
+
+func TestStatusTransitions(t *testing.T) {
+	all := []Status{StatusScheduled, StatusLive, StatusClosed, StatusCancelled, StatusSettled, StatusUnsold}
+	legal := map[[2]Status]bool{
+		{StatusScheduled, StatusLive}:      true,
+		{StatusScheduled, StatusCancelled}: true,
+		{StatusLive, StatusClosed}:         true,
+		{StatusLive, StatusCancelled}:      true,
+		{StatusClosed, StatusSettled}:      true,
+		{StatusClosed, StatusUnsold}:       true,
+	}
+	for _, from := range all {
+		for _, to := range all {
+			if got := from.CanTransitionTo(to); got != legal[[2]Status{from, to}] {
+				t.Errorf("%s.CanTransitionTo(%s) = %v", from, to, got)
+			}
+		}
+	}
+
+	tests := []struct {
+		s        Status
+		terminal bool
+	}{
+		{StatusScheduled, false},
+		{StatusLive, false},
+		{StatusClosed, false},
+		{StatusCancelled, true},
+		{StatusSettled, true},
+		{StatusUnsold, true},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Terminal(); got != tt.terminal {
+			t.Errorf("%s.Terminal() = %v, want %v", tt.s, got, tt.terminal)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package auction
+
+import (
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
+// Summary is a seller's dashboard row for one of their auctions.
+type Summary struct {
+	Auction
+	Cultivar      string
+	TotalWeightKG int
+	// HighestBidPerKG is nil when there are no bids or they are still sealed.
+	HighestBidPerKG      *money.Amount
+	BidCount             int
+	EndsAt               time.Time
+	TimeRemainingSeconds int64
+}
@@ -0,0 +1,58 @@
+package auction
+
+import "errors"
+
+// Type is the auction format.
+type Type string
+
+const (
+	// TypeEnglish is an open ascending auction; the highest bid wins and pays
+	// its own price.
+	TypeEnglish Type = "english"
+	// TypeSealedFirstPrice hides bids until close; the highest bid wins and
+	// pays its own price.
+	TypeSealedFirstPrice Type = "sealed_first_price"
+	// TypeSealedSecondPrice (Vickrey) hides bids until close; the highest
+	// bid wins and pays the second-highest price.
+	TypeSealedSecondPrice Type = "sealed_second_price"
+	// TypeDutch is a descending clock auction; the price drops on a schedule
+	// and the first buyer to accept takes the whole lot at that price.
+	TypeDutch Type = "dutch"
+	// TypeMultiUnit splits the lot's weight among buyers; each bid asks for
+	// a quantity and the highest bids are filled until the weight runs out.
+	TypeMultiUnit Type = "multi_unit"
+)
+
+// Clearing decides what the winners of a multi-unit auction pay.
+type Clearing string
+
+const (
+	// ClearingUniform charges every winner the lowest accepted bid price.
+	ClearingUniform Clearing = "uniform"
+	// ClearingPayAsBid charges each winner their own bid price.
+	ClearingPayAsBid Clearing = "pay_as_bid"
+)
+
+var (
+	ErrUnknownType      = errors.New("unknown auction type")
+	ErrUnknownClearing  = errors.New("unknown clearing rule")
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("prices must be in the auction's currency")
+)
+
+func (t Type) Valid() bool {
+	switch t {
+	case TypeEnglish, TypeSealedFirstPrice, TypeSealedSecondPrice, TypeDutch, TypeMultiUnit:
+		return true
+	}
+	return false
+}
+
+func (c Clearing) Valid() bool {
+	return c == ClearingUniform || c == ClearingPayAsBid
+}
+
+// Sealed reports whether bids stay hidden until the auction closes.
+func (t Type) Sealed() bool {
+	return t == TypeSealedFirstPrice || t == TypeSealedSecondPrice
+}
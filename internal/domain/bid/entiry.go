@@ -1,8 +1,31 @@
 package bid
 
+import (
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
 type Bid struct {
 	ID            int
 	AuctionID     int
 	BuyerID       int
-	BidPricePerKG float64
-}
\ No newline at end of file
+	BidPricePerKG money.Amount
+	// QuantityKG is how much of the lot a multi-unit bid asks for. It is zero
+	// on every other format, where a bid is for the whole lot.
+	QuantityKG int
+	// Proxy is true when the system placed the bid on the buyer's behalf.
+	Proxy bool
+}
+
+// ProxyBid is a buyer's confidential maximum for an auction. The system bids
+// for them in minimum increments up to MaxPricePerKG. The maximum is never
+// exposed to sellers or other buyers. Ties between equal maximums go to the
+// earliest RegisteredAt; raising a maximum counts as a new registration.
+type ProxyBid struct {
+	ID            int
+	AuctionID     int
+	BuyerID       int
+	MaxPricePerKG money.Amount
+	RegisteredAt  time.Time
+}
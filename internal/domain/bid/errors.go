@@ -0,0 +1,37 @@
+package bid
+
+import (
+	"errors"
+	"fmt"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/money"
+)
+
+var (
+	ErrNotFound          = errors.New("bid not found")
+	ErrAuctionNotFound   = auction.ErrNotFound
+	ErrAuctionNotStarted = errors.New("auction has not started yet")
+	ErrAuctionEnded      = errors.New("auction has ended")
+	ErrAuctionCancelled  = errors.New("auction has been cancelled")
+	ErrInvalidPrice      = errors.New("bid price must be greater than zero")
+	ErrWrongCurrency     = errors.New("bid must be in the auction's currency")
+	ErrProxyLowered      = errors.New("proxy maximum can only be raised")
+	ErrProxyNotFound     = errors.New("proxy bid not found")
+	ErrProxyNotSupported = errors.New("proxy bidding is only available on english auctions")
+	ErrAcceptOnly        = errors.New("dutch auctions are won by accepting the current price")
+	ErrNoAcceptPrice     = errors.New("auction has no price to accept")
+	ErrBuyNowUnavailable = errors.New("bidding has already reached the buy-it-now price")
+	ErrInvalidQuantity   = errors.New("quantity must be greater than zero and at most the lot weight")
+	ErrQuantityNotUsed   = errors.New("quantity is only accepted on multi-unit auctions")
+	ErrBidLowered        = errors.New("a revised bid cannot lower its price or quantity")
+)
+
+// BidTooLowError is returned when a bid does not beat the current asking price.
+type BidTooLowError struct {
+	MinimumPerKG money.Amount
+}
+
+func (e *BidTooLowError) Error() string {
+	return fmt.Sprintf("bid must be at least %s %s per kg", e.MinimumPerKG, e.MinimumPerKG.Currency())
+}
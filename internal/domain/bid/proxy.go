@@ -0,0 +1,91 @@
+package bid
+
+import (
+	"sort"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/money"
+)
+
+// resolveProxies lets registered proxies respond to the current state of the
+// auction and returns the bids they placed. The strongest proxy (highest
+// maximum, earliest registration on ties) bids just enough to beat the
+// standing price and every other buyer's maximum, capped at its own maximum.
+func resolveProxies(l Locked) ([]Bid, error) {
+	proxies, err := l.Proxies()
+	if err != nil {
+		return nil, err
+	}
+	if len(proxies) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(proxies, func(i, j int) bool {
+		if c := proxies[i].MaxPricePerKG.Cmp(proxies[j].MaxPricePerKG); c != 0 {
+			return c > 0
+		}
+		if !proxies[i].RegisteredAt.Equal(proxies[j].RegisteredAt) {
+			return proxies[i].RegisteredAt.Before(proxies[j].RegisteredAt)
+		}
+		return proxies[i].ID < proxies[j].ID
+	})
+
+	best := proxies[0]
+	var rival *ProxyBid
+	for i := 1; i < len(proxies); i++ {
+		if proxies[i].BuyerID != best.BuyerID {
+			rival = &proxies[i]
+			break
+		}
+	}
+
+	highest, err := l.Highest()
+	if err != nil {
+		return nil, err
+	}
+
+	a := l.Auction()
+	minimum, err := minimumBid(a, highest)
+	if err != nil {
+		// The standing bid is already the largest amount there is.
+		return nil, nil
+	}
+	target := minimum
+	if highest != nil && highest.BuyerID == best.BuyerID {
+		// Already leading: only respond to a rival proxy that could outbid us.
+		if rival == nil || rival.MaxPricePerKG.Cmp(minimum) < 0 {
+			return nil, nil
+		}
+		target = outbid(a, *rival, best)
+	} else if rival != nil {
+		target = money.Max(target, outbid(a, *rival, best))
+	}
+
+	price := money.Min(best.MaxPricePerKG, target)
+	if price.Cmp(minimum) < 0 {
+		return nil, nil
+	}
+
+	b := Bid{
+		AuctionID:     best.AuctionID,
+		BuyerID:       best.BuyerID,
+		BidPricePerKG: price,
+		Proxy:         true,
+	}
+	id, err := l.CreateBid(b)
+	if err != nil {
+		return nil, err
+	}
+	b.ID = id
+	return []Bid{b}, nil
+}
+
+// outbid is what best has to bid to beat rival's maximum: one increment
+// more. If that is more than any amount can be, best can only match the
+// maximum, which it wins by registering first.
+func outbid(a auction.Auction, rival, best ProxyBid) money.Amount {
+	price, err := rival.MaxPricePerKG.Add(minIncrement(a))
+	if err != nil {
+		return best.MaxPricePerKG
+	}
+	return price
+}
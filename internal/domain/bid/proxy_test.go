@@ -0,0 +1,109 @@
+package bid
+
+import (
+	"testing"
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
+func TestResolveProxies(t *testing.T) {
+	early := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	late := early.Add(time.Minute)
+	proxy := func(id, buyerID int, max int64, at time.Time) ProxyBid {
+		return ProxyBid{ID: id, AuctionID: 1, BuyerID: buyerID, MaxPricePerKG: usd(max), RegisteredAt: at}
+	}
+	tests := []struct {
+		name      string
+		bids      []Bid
+		proxies   []ProxyBid
+		wantBuyer int // 0 means no proxy bids
+		wantPrice money.Amount
+	}{
+		{
+			name:      "opens at the initial price",
+			proxies:   []ProxyBid{proxy(1, 1, 200, early)},
+			wantBuyer: 1, wantPrice: usd(100),
+		},
+		{
+			name:      "beats a standing bid by one increment",
+			bids:      []Bid{{ID: 1, BuyerID: 2, BidPricePerKG: usd(150)}},
+			proxies:   []ProxyBid{proxy(1, 1, 200, early)},
+			wantBuyer: 1, wantPrice: usd(151),
+		},
+		{
+			name:    "maximum below the asking price",
+			bids:    []Bid{{ID: 1, BuyerID: 2, BidPricePerKG: usd(200)}},
+			proxies: []ProxyBid{proxy(1, 1, 200, early)},
+		},
+		{
+			name:      "capped at its maximum",
+			bids:      []Bid{{ID: 1, BuyerID: 2, BidPricePerKG: usd(150)}},
+			proxies:   []ProxyBid{proxy(1, 1, 151, early)},
+			wantBuyer: 1, wantPrice: usd(151),
+		},
+		{
+			name:      "highest maximum bids one increment over the rival's",
+			proxies:   []ProxyBid{proxy(1, 1, 180, early), proxy(2, 2, 200, late)},
+			wantBuyer: 2, wantPrice: usd(181),
+		},
+		{
+			name:      "equal maximums go to the earliest registration",
+			proxies:   []ProxyBid{proxy(1, 1, 200, late), proxy(2, 2, 200, early)},
+			wantBuyer: 2, wantPrice: usd(200),
+		},
+		{
+			name:      "equal registrations go to the lowest id",
+			proxies:   []ProxyBid{proxy(2, 2, 200, early), proxy(1, 1, 200, early)},
+			wantBuyer: 1, wantPrice: usd(200),
+		},
+		{
+			name:    "leader has no rival",
+			bids:    []Bid{{ID: 1, BuyerID: 1, BidPricePerKG: usd(120)}},
+			proxies: []ProxyBid{proxy(1, 1, 200, early)},
+		},
+		{
+			name:    "leader's rival can't reach the asking price",
+			bids:    []Bid{{ID: 1, BuyerID: 1, BidPricePerKG: usd(120)}},
+			proxies: []ProxyBid{proxy(1, 1, 200, early), proxy(2, 2, 120, late)},
+		},
+		{
+			name:      "leader answers a rival proxy",
+			bids:      []Bid{{ID: 1, BuyerID: 1, BidPricePerKG: usd(120)}},
+			proxies:   []ProxyBid{proxy(1, 1, 200, early), proxy(2, 2, 150, late)},
+			wantBuyer: 1, wantPrice: usd(151),
+		},
+		{
+			name:      "rival maximum at the largest amount",
+			proxies:   []ProxyBid{proxy(1, 1, 9999999999999999, early), proxy(2, 2, 9999999999999999, late)},
+			wantBuyer: 1, wantPrice: usd(9999999999999999),
+		},
+		{
+			name:    "standing bid at the largest amount",
+			bids:    []Bid{{ID: 1, BuyerID: 2, BidPricePerKG: usd(9999999999999999)}},
+			proxies: []ProxyBid{proxy(1, 1, 9999999999999999, early)},
+		},
+	}
+	for _, tt := range tests {
+		l := &fakeLocked{a: liveAuction(), bids: tt.bids, proxies: tt.proxies, weight: 1000}
+		placed, err := resolveProxies(l)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tt.wantBuyer == 0 {
+			if len(placed) != 0 {
+				t.Errorf("%s: placed %+v, want nothing", tt.name, placed)
+			}
+			continue
+		}
+		if len(placed) != 1 {
+			t.Errorf("%s: placed %d bids, want 1", tt.name, len(placed))
+			continue
+		}
+		b := placed[0]
+		if b.BuyerID != tt.wantBuyer || b.BidPricePerKG != tt.wantPrice || !b.Proxy || b.ID == 0 {
+			t.Errorf("%s: placed %+v, want a proxy bid for buyer %d at %v", tt.name, b, tt.wantBuyer, tt.wantPrice)
+		}
+	}
+}
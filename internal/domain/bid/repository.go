@@ -1,9 +1,48 @@
 package bid
 
+import (
+	"context"
+
+	"banana-auction/internal/domain/auction"
+)
+
+// Locked gives access to one auction's bids while its row lock is held, so
+// reads and writes made through it can't interleave with other bidders.
+type Locked interface {
+	Auction() auction.Auction
+	// Highest returns the current highest bid, or nil if there are none.
+	Highest() (*Bid, error)
+	Proxies() ([]ProxyBid, error)
+	CreateBid(b Bid) (int, error)
+	// BuyerBid returns the buyer's highest bid, or nil if they have none.
+	BuyerBid(buyerID int) (*Bid, error)
+	DeleteBid(id int) error
+	// SaveProxy inserts or replaces the buyer's proxy for the auction.
+	// Only a new maximum renews its registration time.
+	SaveProxy(p ProxyBid) (int, error)
+	// Extend pushes the auction's end out by minutes and returns the
+	// updated auction.
+	Extend(minutes int) (auction.Auction, error)
+	// LotWeightKG returns the total weight of the auctioned lot.
+	LotWeightKG() (int, error)
+	// Close ends the live auction now with winningBidID as the winner.
+	// Any other status is auction.ErrInvalidTransition.
+	Close(winningBidID int) (auction.Auction, error)
+}
+
 type Repository interface {
-	Create(b Bid) (int, error)
-	GetByID(id int) (Bid, error)
-	Update(b Bid) error
-	Delete(id int) error
-	ListByAuctionID(auctionID int) ([]Bid, error)
-}
\ No newline at end of file
+	Create(ctx context.Context, b Bid) (int, error)
+	// WithAuctionLock runs fn in one transaction holding the auction's row
+	// lock. An error from fn rolls everything back.
+	WithAuctionLock(ctx context.Context, auctionID int, fn func(l Locked) error) error
+	GetByID(ctx context.Context, id int) (Bid, error)
+	// Highest returns the auction's current highest bid, or nil if it has none.
+	Highest(ctx context.Context, auctionID int) (*Bid, error)
+	GetProxy(ctx context.Context, auctionID, buyerID int) (ProxyBid, error)
+	Update(ctx context.Context, b Bid) error
+	Delete(ctx context.Context, id int) error
+	ListByAuctionID(ctx context.Context, auctionID int) ([]Bid, error)
+	// ListByBuyer returns one Summary per auction the buyer has bid on,
+	// most recently ending first.
+	ListByBuyer(ctx context.Context, buyerID int) ([]Summary, error)
+}
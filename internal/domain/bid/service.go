@@ -1,48 +1,491 @@
 package bid
 
+import (
+	"context"
+	"time"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/event"
+	"banana-auction/internal/domain/money"
+)
+
 type Service interface {
-	PlaceBid(auctionID, buyerID int, bidPricePerKG float64) (int, error)
-	GetBid(id int) (Bid, error)
-	UpdateBid(id int, bidPricePerKG float64) error
-	DeleteBid(id int) error
-	ListBids(auctionID int) ([]Bid, error)
+	// PlaceBid places a bid for the whole lot, or for quantityKG of it on a
+	// multi-unit auction. quantityKG must be zero on other formats.
+	PlaceBid(ctx context.Context, auctionID, buyerID int, bidPricePerKG money.Amount, quantityKG int) (int, error)
+	// PlaceProxyBid registers or raises the buyer's confidential maximum and
+	// lets the system bid for them. It reports whether the buyer now leads.
+	PlaceProxyBid(ctx context.Context, auctionID, buyerID int, maxPricePerKG money.Amount) (ProxyResult, error)
+	GetProxyBid(ctx context.Context, auctionID, buyerID int) (ProxyBid, error)
+	// Accept buys the whole lot at a fixed price and closes the auction: the
+	// current clock price of a dutch auction, or an english auction's
+	// buy-it-now price. Only the first buyer to accept wins.
+	Accept(ctx context.Context, auctionID, buyerID int) (Bid, error)
+	GetBid(ctx context.Context, id int) (Bid, error)
+	GetHighestBid(ctx context.Context, auctionID int) (*Bid, error)
+	UpdateBid(ctx context.Context, id int, bidPricePerKG money.Amount) error
+	DeleteBid(ctx context.Context, id int) error
+	ListBids(ctx context.Context, auctionID int) ([]Bid, error)
+	ListBuyerBids(ctx context.Context, buyerID int) ([]Summary, error)
+}
+
+// ProxyResult is the buyer-facing outcome of registering a proxy bid.
+type ProxyResult struct {
+	ProxyID           int
+	Leading           bool
+	CurrentPricePerKG money.Amount
 }
 
 type service struct {
-	repo Repository
+	repo   Repository
+	events event.Publisher
+}
+
+func NewService(repo Repository, events event.Publisher) Service {
+	return &service{repo: repo, events: events}
+}
+
+func (s *service) PlaceBid(ctx context.Context, auctionID, buyerID int, bidPricePerKG money.Amount, quantityKG int) (int, error) {
+	if bidPricePerKG.Sign() <= 0 {
+		return 0, ErrInvalidPrice
+	}
+	if quantityKG < 0 {
+		return 0, ErrInvalidQuantity
+	}
+
+	var id int
+	var previous *Bid
+	var placed []Bid
+	var extendedTo *time.Time
+	var multiUnit bool
+	err := s.repo.WithAuctionLock(ctx, auctionID, func(l Locked) error {
+		now := time.Now()
+		highest, err := l.Highest()
+		if err != nil {
+			return err
+		}
+		if err := checkOpen(l.Auction(), now); err != nil {
+			return err
+		}
+		if bidPricePerKG.Currency() != l.Auction().Currency {
+			return ErrWrongCurrency
+		}
+		if err := checkLotTotal(l, bidPricePerKG); err != nil {
+			return err
+		}
+		if l.Auction().Type == auction.TypeDutch {
+			return ErrAcceptOnly
+		}
+		if l.Auction().Type == auction.TypeMultiUnit {
+			multiUnit = true
+			id, err = placeMultiUnitBid(l, buyerID, bidPricePerKG, quantityKG)
+			return err
+		}
+		if quantityKG != 0 {
+			return ErrQuantityNotUsed
+		}
+		if l.Auction().Type.Sealed() {
+			id, err = placeSealedBid(l, buyerID, bidPricePerKG)
+			return err
+		}
+		minimum, err := minimumBid(l.Auction(), highest)
+		if err != nil {
+			return err
+		}
+		if bidPricePerKG.Cmp(minimum) < 0 {
+			return &BidTooLowError{MinimumPerKG: minimum}
+		}
+
+		b := Bid{
+			AuctionID:     auctionID,
+			BuyerID:       buyerID,
+			BidPricePerKG: bidPricePerKG,
+		}
+		id, err = l.CreateBid(b)
+		if err != nil {
+			return err
+		}
+		b.ID = id
+
+		// Proxies get to answer the new bid straight away.
+		proxyBids, err := resolveProxies(l)
+		if err != nil {
+			return err
+		}
+		previous = highest
+		placed = append([]Bid{b}, proxyBids...)
+
+		extendedTo, err = softClose(l, now)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if multiUnit {
+		// Multi-unit bids don't displace each other, so there is no outbid.
+		s.events.Publish(event.Event{
+			Type:       event.NewBid,
+			AuctionID:  auctionID,
+			BidID:      id,
+			BuyerID:    buyerID,
+			PricePerKG: bidPricePerKG,
+			QuantityKG: quantityKG,
+			At:         time.Now(),
+		})
+		return id, nil
+	}
+	s.publishBids(previous, placed, extendedTo)
+	return id, nil
+}
+
+func (s *service) PlaceProxyBid(ctx context.Context, auctionID, buyerID int, maxPricePerKG money.Amount) (ProxyResult, error) {
+	if maxPricePerKG.Sign() <= 0 {
+		return ProxyResult{}, ErrInvalidPrice
+	}
+
+	var result ProxyResult
+	var previous *Bid
+	var placed []Bid
+	var extendedTo *time.Time
+	err := s.repo.WithAuctionLock(ctx, auctionID, func(l Locked) error {
+		now := time.Now()
+		highest, err := l.Highest()
+		if err != nil {
+			return err
+		}
+		if err := checkOpen(l.Auction(), now); err != nil {
+			return err
+		}
+		if l.Auction().Type != auction.TypeEnglish {
+			return ErrProxyNotSupported
+		}
+		if maxPricePerKG.Currency() != l.Auction().Currency {
+			return ErrWrongCurrency
+		}
+		if err := checkLotTotal(l, maxPricePerKG); err != nil {
+			return err
+		}
+
+		proxies, err := l.Proxies()
+		if err != nil {
+			return err
+		}
+		p := ProxyBid{AuctionID: auctionID, BuyerID: buyerID, MaxPricePerKG: maxPricePerKG}
+		for _, existing := range proxies {
+			if existing.BuyerID != buyerID {
+				continue
+			}
+			if maxPricePerKG.Cmp(existing.MaxPricePerKG) < 0 {
+				return ErrProxyLowered
+			}
+			p.ID = existing.ID
+		}
+		if highest == nil || highest.BuyerID != buyerID {
+			minimum, err := minimumBid(l.Auction(), highest)
+			if err != nil {
+				return err
+			}
+			if maxPricePerKG.Cmp(minimum) < 0 {
+				return &BidTooLowError{MinimumPerKG: minimum}
+			}
+		}
+
+		result.ProxyID, err = l.SaveProxy(p)
+		if err != nil {
+			return err
+		}
+
+		proxyBids, err := resolveProxies(l)
+		if err != nil {
+			return err
+		}
+		previous = highest
+		placed = proxyBids
+		if len(placed) > 0 {
+			if extendedTo, err = softClose(l, now); err != nil {
+				return err
+			}
+		}
+
+		current, err := l.Highest()
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			result.Leading = current.BuyerID == buyerID
+			result.CurrentPricePerKG = current.BidPricePerKG
+		}
+		return nil
+	})
+	if err != nil {
+		return ProxyResult{}, err
+	}
+
+	s.publishBids(previous, placed, extendedTo)
+	return result, nil
+}
+
+func (s *service) Accept(ctx context.Context, auctionID, buyerID int) (Bid, error) {
+	var b Bid
+	var previous *Bid
+	var closed auction.Auction
+	err := s.repo.WithAuctionLock(ctx, auctionID, func(l Locked) error {
+		now := time.Now()
+		if err := checkOpen(l.Auction(), now); err != nil {
+			return err
+		}
+		// Accepting closes the auction, which has to be live first; the
+		// scheduler opens it shortly after its start.
+		if l.Auction().Status == auction.StatusScheduled {
+			return ErrAuctionNotStarted
+		}
+		highest, err := l.Highest()
+		if err != nil {
+			return err
+		}
+		price, err := acceptPrice(l.Auction(), highest, now)
+		if err != nil {
+			return err
+		}
+		if err := checkLotTotal(l, price); err != nil {
+			return err
+		}
+		previous = highest
+
+		b = Bid{AuctionID: auctionID, BuyerID: buyerID, BidPricePerKG: price}
+		if b.ID, err = l.CreateBid(b); err != nil {
+			return err
+		}
+		closed, err = l.Close(b.ID)
+		return err
+	})
+	if err != nil {
+		return Bid{}, err
+	}
+
+	s.publishBids(previous, []Bid{b}, nil)
+	s.events.Publish(event.Event{
+		Type:      event.AuctionClosed,
+		AuctionID: closed.ID,
+		BidID:     b.ID,
+		At:        time.Now(),
+	})
+	return b, nil
+}
+
+func (s *service) GetProxyBid(ctx context.Context, auctionID, buyerID int) (ProxyBid, error) {
+	return s.repo.GetProxy(ctx, auctionID, buyerID)
+}
+
+// publishBids announces placed bids in order, each one outbidding the leader
+// before it, followed by the new end time if soft close extended it.
+func (s *service) publishBids(previous *Bid, placed []Bid, extendedTo *time.Time) {
+	now := time.Now()
+	for i := range placed {
+		b := placed[i]
+		s.events.Publish(event.Event{
+			Type:       event.NewHighBid,
+			AuctionID:  b.AuctionID,
+			BidID:      b.ID,
+			BuyerID:    b.BuyerID,
+			PricePerKG: b.BidPricePerKG,
+			At:         now,
+		})
+		if previous != nil && previous.BuyerID != b.BuyerID {
+			s.events.Publish(event.Event{
+				Type:       event.Outbid,
+				AuctionID:  b.AuctionID,
+				BidID:      previous.ID,
+				BuyerID:    previous.BuyerID,
+				PricePerKG: b.BidPricePerKG,
+				At:         now,
+			})
+		}
+		previous = &b
+	}
+	if extendedTo != nil && len(placed) > 0 {
+		s.events.Publish(event.Event{
+			Type:      event.TimeExtended,
+			AuctionID: placed[0].AuctionID,
+			EndsAt:    extendedTo,
+			At:        now,
+		})
+	}
+}
+
+// softClose extends the auction when a bid lands in its closing window and
+// returns the new end time, or nil if nothing changed.
+func softClose(l Locked, now time.Time) (*time.Time, error) {
+	minutes := l.Auction().SoftCloseExtension(now)
+	if minutes == 0 {
+		return nil, nil
+	}
+	a, err := l.Extend(minutes)
+	if err != nil {
+		return nil, err
+	}
+	end := a.EndTime()
+	return &end, nil
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+// acceptPrice is the fixed price a buyer can take the lot at right now.
+// Buy-it-now goes away once the bidding has reached it.
+func acceptPrice(a auction.Auction, highest *Bid, now time.Time) (money.Amount, error) {
+	if a.Type == auction.TypeDutch {
+		return a.PriceAt(now), nil
+	}
+	if a.BuyNowPricePerKG.IsZero() {
+		return money.Amount{}, ErrNoAcceptPrice
+	}
+	if highest != nil && highest.BidPricePerKG.Cmp(a.BuyNowPricePerKG) >= 0 {
+		return money.Amount{}, ErrBuyNowUnavailable
+	}
+	return a.BuyNowPricePerKG, nil
 }
 
-func (s *service) PlaceBid(auctionID, buyerID int, bidPricePerKG float64) (int, error) {
-	b := Bid{
-		AuctionID:     auctionID,
+// placeSealedBid stores the buyer's single sealed bid, replacing any earlier
+// one. A revision is a new submission, so it goes behind equal bids that
+// were already in. Nothing is published: sealed bids stay hidden until close.
+func placeSealedBid(l Locked, buyerID int, bidPricePerKG money.Amount) (int, error) {
+	if minimum := l.Auction().InitialPricePerKG; bidPricePerKG.Cmp(minimum) < 0 {
+		return 0, &BidTooLowError{MinimumPerKG: minimum}
+	}
+
+	existing, err := l.BuyerBid(buyerID)
+	if err != nil {
+		return 0, err
+	}
+	if existing != nil {
+		if err := l.DeleteBid(existing.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	return l.CreateBid(Bid{
+		AuctionID:     l.Auction().ID,
 		BuyerID:       buyerID,
 		BidPricePerKG: bidPricePerKG,
+	})
+}
+
+// placeMultiUnitBid stores the buyer's bid for part of the lot. Each buyer
+// has one bid, which they can raise in price or quantity by bidding again;
+// like a sealed revision it goes behind equal bids that were already in.
+func placeMultiUnitBid(l Locked, buyerID int, bidPricePerKG money.Amount, quantityKG int) (int, error) {
+	if minimum := l.Auction().InitialPricePerKG; bidPricePerKG.Cmp(minimum) < 0 {
+		return 0, &BidTooLowError{MinimumPerKG: minimum}
+	}
+	weight, err := l.LotWeightKG()
+	if err != nil {
+		return 0, err
+	}
+	if quantityKG <= 0 || quantityKG > weight {
+		return 0, ErrInvalidQuantity
+	}
+
+	existing, err := l.BuyerBid(buyerID)
+	if err != nil {
+		return 0, err
+	}
+	if existing != nil {
+		if bidPricePerKG.Cmp(existing.BidPricePerKG) < 0 || quantityKG < existing.QuantityKG {
+			return 0, ErrBidLowered
+		}
+		if err := l.DeleteBid(existing.ID); err != nil {
+			return 0, err
+		}
 	}
 
-	return s.repo.Create(b)
+	return l.CreateBid(Bid{
+		AuctionID:     l.Auction().ID,
+		BuyerID:       buyerID,
+		BidPricePerKG: bidPricePerKG,
+		QuantityKG:    quantityKG,
+	})
 }
 
-func (s *service) GetBid(id int) (Bid, error) {
-	return s.repo.GetByID(id)
+// checkOpen rejects bids on auctions that are not running at now.
+func checkOpen(a auction.Auction, now time.Time) error {
+	switch {
+	case a.Status == auction.StatusCancelled:
+		return ErrAuctionCancelled
+	case a.Status.Ended():
+		return ErrAuctionEnded
+	}
+
+	if now.Before(a.StartDate) {
+		return ErrAuctionNotStarted
+	}
+	if !now.Before(a.EndTime()) {
+		return ErrAuctionEnded
+	}
+	return nil
 }
 
-func (s *service) UpdateBid(id int, bidPricePerKG float64) error {
-	b, err := s.repo.GetByID(id)
+// checkLotTotal rejects a price per kg whose total for the whole lot would
+// be too large to settle.
+func checkLotTotal(l Locked, pricePerKG money.Amount) error {
+	weight, err := l.LotWeightKG()
+	if err != nil {
+		return err
+	}
+	_, err = pricePerKG.Mul(int64(weight))
+	return err
+}
+
+// minimumBid is the English-auction asking price: max(initial price, highest
+// bid + minimum increment). It is money.ErrTooLarge once the highest bid is
+// the largest amount there is.
+func minimumBid(a auction.Auction, highest *Bid) (money.Amount, error) {
+	if highest == nil {
+		return a.InitialPricePerKG, nil
+	}
+	next, err := highest.BidPricePerKG.Add(minIncrement(a))
+	if err != nil {
+		return money.Amount{}, err
+	}
+	return money.Max(a.InitialPricePerKG, next), nil
+}
+
+// minIncrement is the smallest amount a new bid must add to the current
+// highest bid: one minor unit of the auction's currency, e.g. 0.01 USD.
+func minIncrement(a auction.Auction) money.Amount {
+	return a.Currency.Unit()
+}
+
+func (s *service) GetBid(ctx context.Context, id int) (Bid, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *service) GetHighestBid(ctx context.Context, auctionID int) (*Bid, error) {
+	return s.repo.Highest(ctx, auctionID)
+}
+
+func (s *service) UpdateBid(ctx context.Context, id int, bidPricePerKG money.Amount) error {
+	b, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 	b.BidPricePerKG = bidPricePerKG
-	return s.repo.Update(b)
+	return s.repo.Update(ctx, b)
 }
 
-func (s *service) DeleteBid(id int) error {
-	return s.repo.Delete(id)
+func (s *service) DeleteBid(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
 }
 
-func (s *service) ListBids(auctionID int) ([]Bid, error) {
-	return s.repo.ListByAuctionID(auctionID)
-}
\ No newline at end of file
+func (s *service) ListBids(ctx context.Context, auctionID int) ([]Bid, error) {
+	return s.repo.ListByAuctionID(ctx, auctionID)
+}
+
+func (s *service) ListBuyerBids(ctx context.Context, buyerID int) ([]Summary, error) {
+	summaries, err := s.repo.ListByBuyer(ctx, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		summaries[i].Ended = summaries[i].AuctionStatus.Ended()
+	}
+	return summaries, nil
+}
@@ -0,0 +1,440 @@
+package bid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/event"
+	"banana-auction/internal/domain/money"
+)
+
+func usd(minor int64) money.Amount { return money.New(minor, "USD") }
+
+// fakeLocked is an auction and its bids held in memory. The highest bid is
+// the highest price, the earliest bid on ties.
+type fakeLocked struct {
+	a       auction.Auction
+	bids    []Bid
+	proxies []ProxyBid
+	weight  int
+}
+
+func (l *fakeLocked) Auction() auction.Auction { return l.a }
+
+func (l *fakeLocked) Highest() (*Bid, error) {
+	var best *Bid
+	for i := range l.bids {
+		if best == nil || l.bids[i].BidPricePerKG.Cmp(best.BidPricePerKG) > 0 {
+			best = &l.bids[i]
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	b := *best
+	return &b, nil
+}
+
+func (l *fakeLocked) Proxies() ([]ProxyBid, error) {
+	return append([]ProxyBid(nil), l.proxies...), nil
+}
+
+func (l *fakeLocked) CreateBid(b Bid) (int, error) {
+	b.ID = len(l.bids) + 100
+	l.bids = append(l.bids, b)
+	return b.ID, nil
+}
+
+func (l *fakeLocked) BuyerBid(buyerID int) (*Bid, error) {
+	var best *Bid
+	for i := range l.bids {
+		if l.bids[i].BuyerID == buyerID && (best == nil || l.bids[i].BidPricePerKG.Cmp(best.BidPricePerKG) > 0) {
+			best = &l.bids[i]
+		}
+	}
+	return best, nil
+}
+
+func (l *fakeLocked) DeleteBid(id int) error {
+	for i := range l.bids {
+		if l.bids[i].ID == id {
+			l.bids = append(l.bids[:i], l.bids[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("bid not found")
+}
+
+func (l *fakeLocked) SaveProxy(p ProxyBid) (int, error) {
+	for i := range l.proxies {
+		if l.proxies[i].BuyerID == p.BuyerID {
+			p.ID, p.RegisteredAt = l.proxies[i].ID, l.proxies[i].RegisteredAt
+			if p.MaxPricePerKG != l.proxies[i].MaxPricePerKG {
+				p.RegisteredAt = time.Now()
+			}
+			l.proxies[i] = p
+			return p.ID, nil
+		}
+	}
+	p.ID = len(l.proxies) + 1
+	p.RegisteredAt = time.Now()
+	l.proxies = append(l.proxies, p)
+	return p.ID, nil
+}
+
+func (l *fakeLocked) Extend(minutes int) (auction.Auction, error) {
+	l.a.ExtensionMinutes += minutes
+	return l.a, nil
+}
+
+func (l *fakeLocked) Close(winningBidID int) (auction.Auction, error) {
+	if !l.a.Status.CanTransitionTo(auction.StatusClosed) {
+		return auction.Auction{}, auction.ErrInvalidTransition
+	}
+	l.a.Status = auction.StatusClosed
+	l.a.WinningBidID = &winningBidID
+	return l.a, nil
+}
+
+func (l *fakeLocked) LotWeightKG() (int, error) { return l.weight, nil }
+
+// fakeRepo runs every locked function against one fakeLocked, keeping its
+// changes only when the function succeeds.
+type fakeRepo struct {
+	Repository
+	l *fakeLocked
+}
+
+func (r *fakeRepo) WithAuctionLock(_ context.Context, _ int, fn func(l Locked) error) error {
+	l := *r.l
+	l.bids = append([]Bid(nil), r.l.bids...)
+	l.proxies = append([]ProxyBid(nil), r.l.proxies...)
+	if err := fn(&l); err != nil {
+		return err
+	}
+	*r.l = l
+	return nil
+}
+
+type recorder struct{ events []event.Event }
+
+func (r *recorder) Publish(e event.Event) { r.events = append(r.events, e) }
+
+func (r *recorder) types() []event.Type {
+	var types []event.Type
+	for _, e := range r.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+// liveAuction is an english auction in USD that opened an hour ago and
+// ends in a day.
+func liveAuction() auction.Auction {
+	return auction.Auction{
+		ID:                1,
+		StartDate:         time.Now().Add(-time.Hour),
+		DurationDays:      1,
+		Currency:          "USD",
+		InitialPricePerKG: usd(100),
+		Type:              auction.TypeEnglish,
+		Status:            auction.StatusLive,
+	}
+}
+
+func TestMinimumBid(t *testing.T) {
+	jpy := auction.Auction{Currency: "JPY", InitialPricePerKG: money.New(150, "JPY")}
+	kwd := auction.Auction{Currency: "KWD", InitialPricePerKG: money.New(1000, "KWD")}
+	tests := []struct {
+		name    string
+		a       auction.Auction
+		highest *Bid
+		want    money.Amount
+		wantErr error
+	}{
+		{"no bids", liveAuction(), nil, usd(100), nil},
+		{"one cent more", liveAuction(), &Bid{BidPricePerKG: usd(150)}, usd(151), nil},
+		{"never below the initial price", liveAuction(), &Bid{BidPricePerKG: usd(50)}, usd(100), nil},
+		{"one yen more", jpy, &Bid{BidPricePerKG: money.New(200, "JPY")}, money.New(201, "JPY"), nil},
+		{"one fils more", kwd, &Bid{BidPricePerKG: money.New(1500, "KWD")}, money.New(1501, "KWD"), nil},
+		{"nothing above the largest amount", liveAuction(), &Bid{BidPricePerKG: usd(9999999999999999)}, money.Amount{}, money.ErrTooLarge},
+	}
+	for _, tt := range tests {
+		got, err := minimumBid(tt.a, tt.highest)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("%s: minimumBid = %v, %v; want %v, %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckOpen(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := auction.Auction{StartDate: now.Add(-time.Hour), DurationDays: 1, Status: auction.StatusLive}
+	with := func(f func(*auction.Auction)) auction.Auction {
+		b := a
+		f(&b)
+		return b
+	}
+	tests := []struct {
+		name string
+		a    auction.Auction
+		now  time.Time
+		want error
+	}{
+		{"live", a, now, nil},
+		{"scheduled but started", with(func(a *auction.Auction) { a.Status = auction.StatusScheduled }), now, nil},
+		{"before the start", a, now.Add(-2 * time.Hour), ErrAuctionNotStarted},
+		{"at the end", a, a.EndTime(), ErrAuctionEnded},
+		{"inside an extension", with(func(a *auction.Auction) { a.ExtensionMinutes = 5 }), a.EndTime(), nil},
+		{"closed", with(func(a *auction.Auction) { a.Status = auction.StatusClosed }), now, ErrAuctionEnded},
+		{"settled", with(func(a *auction.Auction) { a.Status = auction.StatusSettled }), now, ErrAuctionEnded},
+		{"cancelled", with(func(a *auction.Auction) { a.Status = auction.StatusCancelled }), now, ErrAuctionCancelled},
+	}
+	for _, tt := range tests {
+		if err := checkOpen(tt.a, tt.now); !errors.Is(err, tt.want) {
+			t.Errorf("%s: checkOpen = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestPlaceBid(t *testing.T) {
+	tests := []struct {
+		name       string
+		bids       []Bid
+		price      money.Amount
+		wantErr    error
+		wantEvents []event.Type
+	}{
+		{"opening bid at the initial price", nil, usd(100), nil, []event.Type{event.NewHighBid}},
+		{"below the initial price", nil, usd(99), &BidTooLowError{}, nil},
+		{"one increment over the leader", []Bid{{ID: 1, BuyerID: 2, BidPricePerKG: usd(150)}}, usd(151), nil, []event.Type{event.NewHighBid, event.Outbid}},
+		{"matching the leader", []Bid{{ID: 1, BuyerID: 2, BidPricePerKG: usd(150)}}, usd(150), &BidTooLowError{}, nil},
+		{"zero price", nil, usd(0), ErrInvalidPrice, nil},
+		{"negative price", nil, usd(-100), ErrInvalidPrice, nil},
+		{"other currency", nil, money.New(100, "EUR"), ErrWrongCurrency, nil},
+		{"lot total too large", nil, usd(1000000000000000), money.ErrTooLarge, nil},
+	}
+	for _, tt := range tests {
+		l := &fakeLocked{a: liveAuction(), bids: tt.bids, weight: 1000}
+		events := &recorder{}
+		_, err := NewService(&fakeRepo{l: l}, events).PlaceBid(context.Background(), 1, 1, tt.price, 0)
+		var tooLow *BidTooLowError
+		if _, ok := tt.wantErr.(*BidTooLowError); ok {
+			if !errors.As(err, &tooLow) {
+				t.Errorf("%s: error = %v, want BidTooLowError", tt.name, err)
+			}
+		} else if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		wantLen := len(tt.bids)
+		if tt.wantErr == nil {
+			wantLen++
+		}
+		if len(l.bids) != wantLen {
+			t.Errorf("%s: %d bids stored, want %d", tt.name, len(l.bids), wantLen)
+		}
+		if got := events.types(); !equalTypes(got, tt.wantEvents) {
+			t.Errorf("%s: events = %v, want %v", tt.name, got, tt.wantEvents)
+		}
+	}
+}
+
+func TestPlaceBidBeatenByProxy(t *testing.T) {
+	l := &fakeLocked{a: liveAuction()}
+	l.proxies = []ProxyBid{{ID: 1, AuctionID: 1, BuyerID: 2, MaxPricePerKG: usd(200), RegisteredAt: time.Now()}}
+	events := &recorder{}
+	svc := NewService(&fakeRepo{l: l}, events)
+
+	if _, err := svc.PlaceBid(context.Background(), 1, 1, usd(150), 0); err != nil {
+		t.Fatal(err)
+	}
+	highest, _ := l.Highest()
+	if highest.BuyerID != 2 || highest.BidPricePerKG != usd(151) || !highest.Proxy {
+		t.Errorf("highest = %+v, want buyer 2's proxy bid at 1.51", highest)
+	}
+	want := []event.Type{event.NewHighBid, event.NewHighBid, event.Outbid}
+	if got := events.types(); !equalTypes(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	if e := events.events[2]; e.BuyerID != 1 || e.PricePerKG != usd(151) {
+		t.Errorf("outbid event = %+v, want buyer 1 outbid at 1.51", e)
+	}
+}
+
+func TestPlaceSealedBid(t *testing.T) {
+	a := liveAuction()
+	a.Type = auction.TypeSealedFirstPrice
+	l := &fakeLocked{a: a, bids: []Bid{{ID: 1, AuctionID: 1, BuyerID: 2, BidPricePerKG: usd(150)}}}
+	events := &recorder{}
+	svc := NewService(&fakeRepo{l: l}, events)
+
+	if _, err := svc.PlaceBid(context.Background(), 1, 1, usd(120), 0); err != nil {
+		t.Fatalf("bid under the leader: %v", err)
+	}
+	if _, err := svc.PlaceBid(context.Background(), 1, 1, usd(110), 0); err != nil {
+		t.Fatalf("lowered revision: %v", err)
+	}
+	if _, err := svc.PlaceBid(context.Background(), 1, 1, usd(99), 0); !errors.As(err, new(*BidTooLowError)) {
+		t.Errorf("below the initial price: error = %v, want BidTooLowError", err)
+	}
+	mine, _ := l.BuyerBid(1)
+	if len(l.bids) != 2 || mine.BidPricePerKG != usd(110) {
+		t.Errorf("bids = %+v, want one bid each with buyer 1 at 1.10", l.bids)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("sealed bids published %v", events.types())
+	}
+}
+
+func TestPlaceMultiUnitBid(t *testing.T) {
+	a := liveAuction()
+	a.Type = auction.TypeMultiUnit
+	tests := []struct {
+		name     string
+		price    money.Amount
+		quantity int
+		wantErr  error
+	}{
+		{"first bid", usd(120), 400, nil},
+		{"raised quantity", usd(120), 500, nil},
+		{"lowered price", usd(110), 500, ErrBidLowered},
+		{"lowered quantity", usd(120), 300, ErrBidLowered},
+		{"no quantity", usd(120), 0, ErrInvalidQuantity},
+		{"more than the lot", usd(120), 1001, ErrInvalidQuantity},
+	}
+	l := &fakeLocked{a: a, weight: 1000}
+	svc := NewService(&fakeRepo{l: l}, &recorder{})
+	for _, tt := range tests {
+		if _, err := svc.PlaceBid(context.Background(), 1, 1, tt.price, tt.quantity); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	mine, _ := l.BuyerBid(1)
+	if len(l.bids) != 1 || mine.QuantityKG != 500 {
+		t.Errorf("bids = %+v, want one bid for 500 kg", l.bids)
+	}
+	if _, err := NewService(&fakeRepo{l: &fakeLocked{a: liveAuction()}}, &recorder{}).PlaceBid(context.Background(), 1, 1, usd(100), 5); !errors.Is(err, ErrQuantityNotUsed) {
+		t.Errorf("quantity on an english auction: error = %v, want ErrQuantityNotUsed", err)
+	}
+}
+
+func TestPlaceBidSoftClose(t *testing.T) {
+	a := liveAuction()
+	a.SoftClose = auction.SoftClose{WindowMinutes: 5, ExtensionMinutes: 3}
+	late := a
+	late.StartDate = time.Now().Add(-24*time.Hour + 2*time.Minute)
+	tests := []struct {
+		name string
+		a    auction.Auction
+		want int
+	}{
+		{"outside the window", a, 0},
+		{"inside the window", late, 3},
+	}
+	for _, tt := range tests {
+		l := &fakeLocked{a: tt.a}
+		events := &recorder{}
+		if _, err := NewService(&fakeRepo{l: l}, events).PlaceBid(context.Background(), 1, 1, usd(100), 0); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if l.a.ExtensionMinutes != tt.want {
+			t.Errorf("%s: extended by %d minutes, want %d", tt.name, l.a.ExtensionMinutes, tt.want)
+		}
+		last := events.events[len(events.events)-1]
+		if extended := last.Type == event.TimeExtended; extended != (tt.want > 0) {
+			t.Errorf("%s: events = %v", tt.name, events.types())
+		} else if extended && !last.EndsAt.Equal(l.a.EndTime()) {
+			t.Errorf("%s: EndsAt = %v, want %v", tt.name, last.EndsAt, l.a.EndTime())
+		}
+	}
+}
+
+func TestPlaceProxyBid(t *testing.T) {
+	l := &fakeLocked{a: liveAuction(), bids: []Bid{{ID: 1, AuctionID: 1, BuyerID: 2, BidPricePerKG: usd(150)}}}
+	svc := NewService(&fakeRepo{l: l}, &recorder{})
+
+	res, err := svc.PlaceProxyBid(context.Background(), 1, 1, usd(200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Leading || res.CurrentPricePerKG != usd(151) {
+		t.Errorf("result = %+v, want leading at 1.51", res)
+	}
+	if _, err := svc.PlaceProxyBid(context.Background(), 1, 1, usd(180)); !errors.Is(err, ErrProxyLowered) {
+		t.Errorf("lowering: error = %v, want ErrProxyLowered", err)
+	}
+	registered := l.proxies[0].RegisteredAt
+	if _, err := svc.PlaceProxyBid(context.Background(), 1, 1, usd(200)); err != nil {
+		t.Fatal(err)
+	}
+	if !l.proxies[0].RegisteredAt.Equal(registered) {
+		t.Error("resubmitting the same maximum renewed the registration")
+	}
+}
+
+func TestAccept(t *testing.T) {
+	buyNow := liveAuction()
+	buyNow.BuyNowPricePerKG = usd(300)
+	dutch := liveAuction()
+	dutch.Type = auction.TypeDutch
+	dutch.StartDate = time.Now().Add(-time.Hour - time.Minute)
+	dutch.InitialPricePerKG = usd(200)
+	dutch.Dutch = auction.Dutch{FloorPricePerKG: usd(50), DecrementPerKG: usd(10), DecrementIntervalMinutes: 15}
+	scheduled := buyNow
+	scheduled.Status = auction.StatusScheduled
+	closed := buyNow
+	closed.Status = auction.StatusClosed
+	tests := []struct {
+		name    string
+		a       auction.Auction
+		bids    []Bid
+		want    money.Amount
+		wantErr error
+	}{
+		{"dutch clock price", dutch, nil, usd(160), nil},
+		{"buy it now", buyNow, nil, usd(300), nil},
+		{"buy it now over a bid", buyNow, []Bid{{ID: 1, BuyerID: 2, BidPricePerKG: usd(250)}}, usd(300), nil},
+		{"bidding reached buy it now", buyNow, []Bid{{ID: 1, BuyerID: 2, BidPricePerKG: usd(300)}}, money.Amount{}, ErrBuyNowUnavailable},
+		{"no buy it now price", liveAuction(), nil, money.Amount{}, ErrNoAcceptPrice},
+		{"not opened yet", scheduled, nil, money.Amount{}, ErrAuctionNotStarted},
+		{"already closed", closed, nil, money.Amount{}, ErrAuctionEnded},
+	}
+	for _, tt := range tests {
+		l := &fakeLocked{a: tt.a, bids: tt.bids, weight: 1000}
+		events := &recorder{}
+		b, err := NewService(&fakeRepo{l: l}, events).Accept(context.Background(), 1, 1)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			if l.a.Status != tt.a.Status || len(l.bids) != len(tt.bids) {
+				t.Errorf("%s: failed accept changed the auction", tt.name)
+			}
+			continue
+		}
+		if b.BidPricePerKG != tt.want || l.a.Status != auction.StatusClosed || *l.a.WinningBidID != b.ID {
+			t.Errorf("%s: bid %+v, auction %+v; want the lot sold at %v to bid %d", tt.name, b, l.a, tt.want, b.ID)
+		}
+		if got := events.types(); got[len(got)-1] != event.AuctionClosed {
+			t.Errorf("%s: events = %v, want auction_closed last", tt.name, got)
+		}
+	}
+}
+
+func equalTypes(a, b []event.Type) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
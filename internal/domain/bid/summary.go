@@ -0,0 +1,25 @@
+package bid
+
+import (
+	"time"
+
+	"banana-auction/internal/domain/auction"
+)
+
+// Summary is one auction in a buyer's "my bids" view: their best bid there
+// and how it stands.
+type Summary struct {
+	AuctionID     int
+	AuctionType   auction.Type
+	AuctionStatus auction.Status
+	CancelReason  string
+	EndsAt        time.Time
+	BestBid       Bid
+	BidCount      int
+	// Leading is nil once the auction has ended, while sealed bids are
+	// hidden, and on multi-unit auctions, which have no single leader.
+	Leading *bool
+	Ended   bool
+	// Won is only set once the auction is settled.
+	Won bool
+}
@@ -0,0 +1,92 @@
+package catalog
+
+import (
+	"time"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/money"
+)
+
+// Listing is a live or upcoming auction as buyers browse it, joined with its
+// lot. It never carries the reserve amount or who is bidding.
+type Listing struct {
+	AuctionID         int
+	LotID             int
+	Type              auction.Type
+	Status            auction.Status
+	StartsAt          time.Time
+	EndsAt            time.Time
+	Currency          money.Currency
+	InitialPricePerKG money.Amount
+	// CurrentPricePerKG is the highest bid, the dutch clock price, or the
+	// initial price when there is nothing else to show (including while
+	// sealed bids are hidden).
+	CurrentPricePerKG money.Amount
+	BuyNowPricePerKG  money.Amount
+	// Indicative is only set when the buyer asked to see prices in another
+	// currency and the rate table has a rate for it.
+	Indicative *Indicative
+	// ReserveMet is nil when the auction has no reserve or its bids are sealed.
+	ReserveMet     *bool
+	Cultivar       string
+	PlantedCountry string
+	HarvestDate    time.Time
+	TotalWeightKG  int
+}
+
+// Indicative is a listing's prices converted into the currency a buyer asked
+// for, at the Rate shown. It is for orientation only: bids are placed and
+// settled in the listing's own currency.
+type Indicative struct {
+	Currency          money.Currency
+	InitialPricePerKG money.Amount
+	CurrentPricePerKG money.Amount
+	BuyNowPricePerKG  money.Amount
+	Rate              fx.Rate
+}
+
+// Sort orders listings. Amounts in different currencies don't compare, so
+// the price sorts group listings by currency code first.
+type Sort string
+
+const (
+	SortEndingSoonest Sort = "ending_soonest"
+	SortPriceAsc      Sort = "price_asc"
+	SortPriceDesc     Sort = "price_desc"
+)
+
+// Filter narrows the catalogue. Zero values mean no filter. Harvest dates
+// are calendar days at midnight UTC and both ranges are inclusive. A price
+// bound only matches auctions in the bound's currency.
+type Filter struct {
+	Cultivar       string
+	PlantedCountry string
+	HarvestFrom    time.Time
+	HarvestTo      time.Time
+	MinWeightKG    int
+	MaxWeightKG    int
+	MinPricePerKG  money.Amount
+	MaxPricePerKG  money.Amount
+	Sort           Sort
+	Limit          int
+	// DisplayCurrency asks for indicative prices in that currency.
+	DisplayCurrency money.Currency
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+}
+
+// Page is one page of results. NextCursor is empty on the last page.
+type Page struct {
+	Listings   []Listing
+	NextCursor string
+}
+
+// Cursor is the position after the last listing of a page, in the order of
+// the requested Sort.
+type Cursor struct {
+	Sort       Sort
+	EndsAt     time.Time
+	PricePerKG money.Amount
+	AuctionID  int
+}
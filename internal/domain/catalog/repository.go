@@ -0,0 +1,9 @@
+package catalog
+
+import "context"
+
+type Repository interface {
+	// Search returns up to limit listings matching f in f.Sort order,
+	// starting after the cursor position if it is not nil.
+	Search(ctx context.Context, f Filter, after *Cursor, limit int) ([]Listing, error)
+}
@@ -0,0 +1,189 @@
+package catalog
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/money"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+type Service interface {
+	// Browse lists live and upcoming auctions matching f, one page at a time.
+	Browse(ctx context.Context, f Filter) (Page, error)
+}
+
+type service struct {
+	repo  Repository
+	fxSvc fx.Service
+}
+
+func NewService(repo Repository, fxSvc fx.Service) Service {
+	return &service{repo: repo, fxSvc: fxSvc}
+}
+
+func (s *service) Browse(ctx context.Context, f Filter) (Page, error) {
+	if err := normalize(&f); err != nil {
+		return Page{}, err
+	}
+
+	var after *Cursor
+	if f.Cursor != "" {
+		c, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		if c.Sort != f.Sort {
+			return Page{}, ErrInvalidCursor
+		}
+		after = &c
+	}
+
+	// Ask for one extra row to learn whether there is a next page.
+	listings, err := s.repo.Search(ctx, f, after, f.Limit+1)
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Listings: listings}
+	if len(listings) > f.Limit {
+		page.Listings = listings[:f.Limit]
+		last := page.Listings[f.Limit-1]
+		c := Cursor{Sort: f.Sort, AuctionID: last.AuctionID}
+		if f.Sort == SortEndingSoonest {
+			c.EndsAt = last.EndsAt
+		} else {
+			c.PricePerKG = last.CurrentPricePerKG
+		}
+		page.NextCursor = encodeCursor(c)
+	}
+	if f.DisplayCurrency != "" {
+		if err := s.convert(ctx, page.Listings, f.DisplayCurrency); err != nil {
+			return Page{}, err
+		}
+	}
+	return page, nil
+}
+
+// convert fills in the indicative prices of listings in c. Listings already
+// priced in c, or in a currency the rate table can't convert to c, get none.
+func (s *service) convert(ctx context.Context, listings []Listing, c money.Currency) error {
+	rates := map[money.Currency]*fx.Rate{}
+	for i := range listings {
+		l := &listings[i]
+		if l.Currency == c {
+			continue
+		}
+		rate, looked := rates[l.Currency]
+		if !looked {
+			r, err := s.fxSvc.Find(ctx, l.Currency, c)
+			if err != nil && !errors.Is(err, fx.ErrNotFound) {
+				return err
+			}
+			if err == nil {
+				rate = &r
+			}
+			rates[l.Currency] = rate
+		}
+		if rate == nil {
+			continue
+		}
+		ind := Indicative{Currency: c, Rate: *rate}
+		var err error
+		if ind.InitialPricePerKG, err = rate.Convert(l.InitialPricePerKG); err != nil {
+			return err
+		}
+		if ind.CurrentPricePerKG, err = rate.Convert(l.CurrentPricePerKG); err != nil {
+			return err
+		}
+		if ind.BuyNowPricePerKG, err = rate.Convert(l.BuyNowPricePerKG); err != nil {
+			return err
+		}
+		l.Indicative = &ind
+	}
+	return nil
+}
+
+func normalize(f *Filter) error {
+	switch f.Sort {
+	case "":
+		f.Sort = SortEndingSoonest
+	case SortEndingSoonest, SortPriceAsc, SortPriceDesc:
+	default:
+		return fmt.Errorf("%w: unknown sort", ErrInvalidFilter)
+	}
+
+	if f.DisplayCurrency != "" && !f.DisplayCurrency.Valid() {
+		return fmt.Errorf("%w: unknown display currency", ErrInvalidFilter)
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+
+	if !f.HarvestFrom.IsZero() && !f.HarvestTo.IsZero() && f.HarvestFrom.After(f.HarvestTo) {
+		return fmt.Errorf("%w: harvest_from is after harvest_to", ErrInvalidFilter)
+	}
+	if f.MinWeightKG < 0 || f.MaxWeightKG < 0 || f.MinPricePerKG.Sign() < 0 || f.MaxPricePerKG.Sign() < 0 {
+		return fmt.Errorf("%w: ranges cannot be negative", ErrInvalidFilter)
+	}
+	if !f.MinPricePerKG.IsZero() && !f.MaxPricePerKG.IsZero() && f.MinPricePerKG.Currency() != f.MaxPricePerKG.Currency() {
+		return fmt.Errorf("%w: price bounds are in different currencies", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// cursorJSON is how a Cursor is written into its opaque token. The price
+// travels as a decimal string and its currency so it reads back exactly.
+type cursorJSON struct {
+	Sort       Sort           `json:"s"`
+	EndsAt     time.Time      `json:"e,omitzero"`
+	PricePerKG string         `json:"p,omitempty"`
+	Currency   money.Currency `json:"c,omitempty"`
+	AuctionID  int            `json:"a"`
+}
+
+func encodeCursor(c Cursor) string {
+	w := cursorJSON{Sort: c.Sort, EndsAt: c.EndsAt, AuctionID: c.AuctionID}
+	if c.PricePerKG.Currency() != "" {
+		w.PricePerKG = c.PricePerKG.String()
+		w.Currency = c.PricePerKG.Currency()
+	}
+	b, _ := json.Marshal(w)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var w cursorJSON
+	if err := json.Unmarshal(b, &w); err != nil || w.AuctionID == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	c := Cursor{Sort: w.Sort, EndsAt: w.EndsAt, AuctionID: w.AuctionID}
+	if w.Currency != "" {
+		if c.PricePerKG, err = money.Parse(w.PricePerKG, w.Currency); err != nil {
+			return Cursor{}, ErrInvalidCursor
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,130 @@
+package catalog
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	endsAt := time.Date(2026, 3, 1, 12, 30, 0, 123000000, time.UTC)
+	tests := []Cursor{
+		{Sort: SortEndingSoonest, EndsAt: endsAt, AuctionID: 7},
+		{Sort: SortPriceAsc, PricePerKG: money.New(125, "USD"), AuctionID: 8},
+		{Sort: SortPriceDesc, PricePerKG: money.New(150, "JPY"), AuctionID: 9},
+		{Sort: SortPriceAsc, PricePerKG: money.New(1234, "KWD"), AuctionID: 10},
+		{Sort: SortPriceDesc, PricePerKG: money.Zero("EUR"), AuctionID: 11},
+	}
+	for _, want := range tests {
+		got, err := decodeCursor(encodeCursor(want))
+		if err != nil {
+			t.Errorf("decodeCursor(encodeCursor(%+v)): %v", want, err)
+			continue
+		}
+		if got.Sort != want.Sort || !got.EndsAt.Equal(want.EndsAt) ||
+			got.PricePerKG != want.PricePerKG || got.AuctionID != want.AuctionID {
+			t.Errorf("round trip of %+v = %+v", want, got)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	enc := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"not base64", "!!!"},
+		{"not json", enc("nope")},
+		{"no auction", enc(`{"s":"price_asc","p":"1.25","c":"USD"}`)},
+		{"bad price", enc(`{"s":"price_asc","p":"1.255","c":"USD","a":1}`)},
+		{"unknown currency", enc(`{"s":"price_asc","p":"1.25","c":"XXX","a":1}`)},
+	}
+	for _, tt := range tests {
+		if _, err := decodeCursor(tt.in); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: error = %v, want ErrInvalidCursor", tt.name, err)
+		}
+	}
+}
+
+type fakeRepo struct {
+	listings []Listing
+	after    *Cursor
+	limit    int
+}
+
+func (r *fakeRepo) Search(_ context.Context, _ Filter, after *Cursor, limit int) ([]Listing, error) {
+	r.after, r.limit = after, limit
+	if len(r.listings) > limit {
+		return r.listings[:limit], nil
+	}
+	return r.listings, nil
+}
+
+func TestBrowsePages(t *testing.T) {
+	endsAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	listings := []Listing{
+		{AuctionID: 1, EndsAt: endsAt, CurrentPricePerKG: money.New(100, "USD")},
+		{AuctionID: 2, EndsAt: endsAt.Add(time.Hour), CurrentPricePerKG: money.New(120, "USD")},
+		{AuctionID: 3, EndsAt: endsAt.Add(2 * time.Hour), CurrentPricePerKG: money.New(130, "USD")},
+	}
+	tests := []struct {
+		name     string
+		f        Filter
+		wantLen  int
+		wantNext *Cursor
+	}{
+		{"last page", Filter{Limit: 3}, 3, nil},
+		{"ending soonest", Filter{Limit: 2}, 2, &Cursor{Sort: SortEndingSoonest, EndsAt: listings[1].EndsAt, AuctionID: 2}},
+		{"by price", Filter{Limit: 2, Sort: SortPriceAsc}, 2, &Cursor{Sort: SortPriceAsc, PricePerKG: money.New(120, "USD"), AuctionID: 2}},
+	}
+	for _, tt := range tests {
+		repo := &fakeRepo{listings: listings}
+		page, err := NewService(repo, nil).Browse(context.Background(), tt.f)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if repo.limit != tt.f.Limit+1 {
+			t.Errorf("%s: searched for %d listings, want %d", tt.name, repo.limit, tt.f.Limit+1)
+		}
+		if len(page.Listings) != tt.wantLen {
+			t.Errorf("%s: got %d listings, want %d", tt.name, len(page.Listings), tt.wantLen)
+		}
+		if tt.wantNext == nil {
+			if page.NextCursor != "" {
+				t.Errorf("%s: NextCursor = %q, want none", tt.name, page.NextCursor)
+			}
+			continue
+		}
+		next, err := decodeCursor(page.NextCursor)
+		if err != nil {
+			t.Errorf("%s: decoding NextCursor: %v", tt.name, err)
+			continue
+		}
+		if next != *tt.wantNext {
+			t.Errorf("%s: NextCursor = %+v, want %+v", tt.name, next, *tt.wantNext)
+		}
+
+		// The cursor leads to the next page in the same order.
+		f := tt.f
+		f.Cursor = page.NextCursor
+		if _, err := NewService(repo, nil).Browse(context.Background(), f); err != nil {
+			t.Errorf("%s: next page: %v", tt.name, err)
+		} else if repo.after == nil || *repo.after != next {
+			t.Errorf("%s: next page searched after %+v, want %+v", tt.name, repo.after, next)
+		}
+	}
+}
+
+func TestBrowseRejectsCursorOfAnotherSort(t *testing.T) {
+	c := encodeCursor(Cursor{Sort: SortPriceAsc, PricePerKG: money.New(100, "USD"), AuctionID: 1})
+	_, err := NewService(&fakeRepo{}, nil).Browse(context.Background(), Filter{Sort: SortPriceDesc, Cursor: c})
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("error = %v, want ErrInvalidCursor", err)
+	}
+}
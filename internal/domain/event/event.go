@@ -0,0 +1,38 @@
+package event
+
+import (
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
+type Type string
+
+const (
+	NewHighBid    Type = "new_high_bid"
+	NewBid        Type = "new_bid"
+	Outbid        Type = "outbid"
+	TimeExtended  Type = "time_extended"
+	AuctionClosed Type = "auction_closed"
+	// AuctionCancelled carries the seller's Reason.
+	AuctionCancelled Type = "auction_cancelled"
+)
+
+// Event is something that happened to an auction that live subscribers care
+// about. Fields that don't apply to a Type are left zero.
+type Event struct {
+	Type       Type
+	AuctionID  int
+	BidID      int
+	BuyerID    int
+	PricePerKG money.Amount
+	QuantityKG int
+	EndsAt     *time.Time
+	Reason     string
+	At         time.Time
+}
+
+// Publisher delivers events to subscribers. Publish must not block.
+type Publisher interface {
+	Publish(e Event)
+}
@@ -0,0 +1,66 @@
+package fx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"banana-auction/internal/domain/money"
+)
+
+// csvHeader is the first line every rate file starts with.
+var csvHeader = []string{"base", "quote", "rate"}
+
+// ParseCSV reads a rate file such as
+//
+//	base,quote,rate
+//	USD,EUR,0.92
+//	USD,COP,4100.5
+//
+// Currency codes are case-insensitive and rates have at most six decimal
+// places. Errors name the offending line.
+func ParseCSV(r io.Reader) ([]Rate, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidRate)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRate, err)
+	}
+	for i, name := range csvHeader {
+		if !strings.EqualFold(strings.TrimSpace(header[i]), name) {
+			return nil, fmt.Errorf("%w: the first line must be %s", ErrInvalidRate, strings.Join(csvHeader, ","))
+		}
+	}
+
+	var rates []Rate
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRate, err)
+		}
+		line, _ := cr.FieldPos(0)
+		rate, err := money.ParseRate(strings.TrimSpace(record[2]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRate, line, err)
+		}
+		r := Rate{
+			Base:  money.Currency(strings.ToUpper(strings.TrimSpace(record[0]))),
+			Quote: money.Currency(strings.ToUpper(strings.TrimSpace(record[1]))),
+			Rate:  rate,
+		}
+		if err := validate(r); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRate, line, err)
+		}
+		rates = append(rates, r)
+	}
+}
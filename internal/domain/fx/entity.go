@@ -0,0 +1,33 @@
+// Package fx keeps the marketplace's table of exchange rates. Rates are
+// entered by an admin or imported from CSV rather than fed live, and they
+// only ever produce indicative prices and the snapshot kept on a settlement:
+// bids are placed and settled in the auction's own currency.
+package fx
+
+import (
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
+// Rate is one entry of the table: one unit of Base is worth Rate units of
+// Quote. A pair is stored in one direction only and converts both ways.
+type Rate struct {
+	Base      money.Currency
+	Quote     money.Currency
+	Rate      money.Rate
+	UpdatedAt time.Time
+}
+
+// Converts reports whether r converts between the currencies a and b.
+func (r Rate) Converts(a, b money.Currency) bool {
+	return r.Base == a && r.Quote == b || r.Base == b && r.Quote == a
+}
+
+// Convert converts amt, which must be in Base or Quote, into the other one.
+func (r Rate) Convert(amt money.Amount) (money.Amount, error) {
+	if amt.Currency() == r.Base {
+		return amt.Exchange(r.Rate, r.Quote)
+	}
+	return amt.ExchangeInverse(r.Rate, r.Base)
+}
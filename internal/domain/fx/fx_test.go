@@ -0,0 +1,112 @@
+package fx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"banana-auction/internal/domain/money"
+)
+
+func rate(t *testing.T, base, quote money.Currency, r string) Rate {
+	t.Helper()
+	parsed, err := money.ParseRate(r)
+	if err != nil {
+		t.Fatalf("ParseRate(%q): %v", r, err)
+	}
+	return Rate{Base: base, Quote: quote, Rate: parsed}
+}
+
+func TestConvert(t *testing.T) {
+	usdEUR := rate(t, "USD", "EUR", "0.92")
+	usdJPY := rate(t, "USD", "JPY", "150.5")
+	tests := []struct {
+		name string
+		r    Rate
+		amt  money.Amount
+		want money.Amount
+	}{
+		{"base to quote", usdEUR, money.New(1000, "USD"), money.New(920, "EUR")},
+		{"quote to base", usdEUR, money.New(920, "EUR"), money.New(1000, "USD")},
+		{"rounds to the cent", usdEUR, money.New(1, "EUR"), money.New(1, "USD")},
+		{"into a currency without decimals", usdJPY, money.New(1000, "USD"), money.New(1505, "JPY")},
+		{"out of a currency without decimals", usdJPY, money.New(1505, "JPY"), money.New(1000, "USD")},
+		{"zero", usdEUR, money.Zero("USD"), money.Zero("EUR")},
+	}
+	for _, tt := range tests {
+		got, err := tt.r.Convert(tt.amt)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: Convert(%v %s) = %v %s, %v; want %v %s", tt.name,
+				tt.amt, tt.amt.Currency(), got, got.Currency(), err, tt.want, tt.want.Currency())
+		}
+	}
+}
+
+func TestConverts(t *testing.T) {
+	r := rate(t, "USD", "EUR", "0.92")
+	tests := []struct {
+		a, b money.Currency
+		want bool
+	}{
+		{"USD", "EUR", true},
+		{"EUR", "USD", true},
+		{"USD", "JPY", false},
+		{"EUR", "EUR", false},
+	}
+	for _, tt := range tests {
+		if got := r.Converts(tt.a, tt.b); got != tt.want {
+			t.Errorf("Converts(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []Rate
+		wantErr string
+	}{
+		{
+			name: "rates",
+			in:   "base,quote,rate\nUSD,EUR,0.92\nusd, cop ,4100.5\n",
+			want: []Rate{rate(t, "USD", "EUR", "0.92"), rate(t, "USD", "COP", "4100.5")},
+		},
+		{
+			name: "header in any case",
+			in:   "Base,QUOTE,rate\r\nEUR,GBP,0.85\r\n",
+			want: []Rate{rate(t, "EUR", "GBP", "0.85")},
+		},
+		{name: "header only", in: "base,quote,rate\n"},
+		{name: "empty file", in: "", wantErr: "the file is empty"},
+		{name: "wrong header", in: "from,to,rate\nUSD,EUR,0.92\n", wantErr: "the first line must be base,quote,rate"},
+		{name: "bad rate", in: "base,quote,rate\nUSD,EUR,0.92\nUSD,JPY,abc\n", wantErr: "line 3"},
+		{name: "too many decimals", in: "base,quote,rate\nUSD,EUR,0.1234567\n", wantErr: "line 2"},
+		{name: "unknown currency", in: "base,quote,rate\nUSD,XXX,1\n", wantErr: `unknown currency "XXX"`},
+		{name: "same currency", in: "base,quote,rate\nUSD,USD,1\n", wantErr: "converts a currency into itself"},
+		{name: "zero rate", in: "base,quote,rate\nUSD,EUR,0\n", wantErr: "must be positive"},
+		{name: "missing field", in: "base,quote,rate\nUSD,EUR\n", wantErr: "wrong number of fields"},
+	}
+	for _, tt := range tests {
+		got, err := ParseCSV(strings.NewReader(tt.in))
+		if tt.wantErr != "" {
+			if !errors.Is(err, ErrInvalidRate) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want ErrInvalidRate mentioning %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d rates, want %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: rate %d = %+v, want %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
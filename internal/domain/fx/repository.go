@@ -0,0 +1,22 @@
+package fx
+
+import (
+	"context"
+	"errors"
+
+	"banana-auction/internal/domain/money"
+)
+
+var ErrNotFound = errors.New("exchange rate not found")
+
+type Repository interface {
+	// List returns every rate ordered by base and quote currency.
+	List(ctx context.Context) ([]Rate, error)
+	// Get returns the rate stored for base/quote, not its inverse.
+	Get(ctx context.Context, base, quote money.Currency) (Rate, error)
+	// Save inserts the rates or replaces those already stored for the same
+	// base/quote, all or nothing. UpdatedAt is set to the time of saving.
+	Save(ctx context.Context, rates []Rate) error
+	// Delete returns ErrNotFound if there is no rate for base/quote.
+	Delete(ctx context.Context, base, quote money.Currency) error
+}
@@ -0,0 +1,99 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/transaction"
+)
+
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+type Service interface {
+	ListRates(ctx context.Context) ([]Rate, error)
+	// SetRates saves rates as one batch. A rate replaces the one stored for
+	// its pair in either direction, so USD/EUR supersedes EUR/USD.
+	SetRates(ctx context.Context, rates []Rate) error
+	// ImportCSV saves the rates in a CSV file like SetRates and returns how
+	// many there were. See ParseCSV for the format.
+	ImportCSV(ctx context.Context, r io.Reader) (int, error)
+	DeleteRate(ctx context.Context, base, quote money.Currency) error
+	// Find returns the rate converting between from and to, whichever way
+	// round it is stored, or ErrNotFound.
+	Find(ctx context.Context, from, to money.Currency) (Rate, error)
+}
+
+type service struct {
+	repo Repository
+	tx   transaction.Manager
+}
+
+func NewService(repo Repository, tx transaction.Manager) Service {
+	return &service{repo: repo, tx: tx}
+}
+
+func (s *service) ListRates(ctx context.Context) ([]Rate, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *service) SetRates(ctx context.Context, rates []Rate) error {
+	for i, r := range rates {
+		if err := validate(r); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRate, err)
+		}
+		for _, other := range rates[:i] {
+			if other.Converts(r.Base, r.Quote) {
+				return fmt.Errorf("%w: %s/%s is listed twice", ErrInvalidRate, r.Base, r.Quote)
+			}
+		}
+	}
+	return s.tx.Do(ctx, transaction.ReadCommitted, func(ctx context.Context) error {
+		for _, r := range rates {
+			err := s.repo.Delete(ctx, r.Quote, r.Base)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+		}
+		return s.repo.Save(ctx, rates)
+	})
+}
+
+func validate(r Rate) error {
+	switch {
+	case !r.Base.Valid():
+		return fmt.Errorf("unknown currency %q", r.Base)
+	case !r.Quote.Valid():
+		return fmt.Errorf("unknown currency %q", r.Quote)
+	case r.Base == r.Quote:
+		return fmt.Errorf("%s/%s converts a currency into itself", r.Base, r.Quote)
+	case r.Rate.Millionths() <= 0:
+		return fmt.Errorf("%s/%s must be positive", r.Base, r.Quote)
+	}
+	return nil
+}
+
+func (s *service) ImportCSV(ctx context.Context, r io.Reader) (int, error) {
+	rates, err := ParseCSV(r)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.SetRates(ctx, rates); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+func (s *service) DeleteRate(ctx context.Context, base, quote money.Currency) error {
+	return s.repo.Delete(ctx, base, quote)
+}
+
+func (s *service) Find(ctx context.Context, from, to money.Currency) (Rate, error) {
+	r, err := s.repo.Get(ctx, from, to)
+	if errors.Is(err, ErrNotFound) {
+		return s.repo.Get(ctx, to, from)
+	}
+	return r, err
+}
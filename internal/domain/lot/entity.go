@@ -1,10 +1,13 @@
 package lot
 
+import "time"
+
 type Lot struct {
 	ID             int
 	SellerID       int
 	Cultivar       string
 	PlantedCountry string
-	HarvestDate    string
-	TotalWeightKG  int
-}
\ No newline at end of file
+	// HarvestDate is a calendar day, held as midnight UTC.
+	HarvestDate   time.Time
+	TotalWeightKG int
+}
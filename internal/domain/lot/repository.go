@@ -1,9 +1,34 @@
 package lot
 
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrNotFound = errors.New("lot not found")
+	// ErrSettled means the lot was sold or went unsold and has a settlement
+	// record, which is never deleted.
+	ErrSettled = errors.New("lot has been settled")
+	// ErrAuctionStarted means the lot has an auction that has left the
+	// scheduled state without being cancelled; its bids are kept, so the
+	// lot stays too.
+	ErrAuctionStarted = errors.New("lot has an auction that is no longer scheduled")
+	// ErrHasAuction means the lot's auction is still scheduled and has to be
+	// cancelled, so its bidders are told, before the lot can go.
+	ErrHasAuction = errors.New("lot has a scheduled auction; cancel it first")
+)
+
 type Repository interface {
-	Create(l Lot) (int, error)
-	GetByID(id int) (Lot, error)
-	Update(l Lot) error
-	Delete(id int) error
-	List() ([]Lot, error)
+	Create(ctx context.Context, l Lot) (int, error)
+	GetByID(ctx context.Context, id int) (Lot, error)
+	Update(ctx context.Context, l Lot) error
+	// Delete removes the lot. A lot whose auctions were all cancelled goes
+	// with those auctions and their bids. Any other auction keeps the lot:
+	// Delete returns ErrSettled, ErrAuctionStarted or ErrHasAuction instead.
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context) ([]Lot, error)
+	// ListBySeller returns the seller's lots with their auction's status,
+	// bid aggregates and time remaining, newest first.
+	ListBySeller(ctx context.Context, sellerID int) ([]Summary, error)
 }
\ No newline at end of file
@@ -1,26 +1,45 @@
 package lot
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"banana-auction/internal/domain/transaction"
+)
+
+var (
+	ErrHarvestRequired = errors.New("a harvest date is required")
+	ErrHarvestInFuture = errors.New("harvest date cannot be in the future")
+	ErrTooLight        = errors.New("minimum weight allowed is 1000 kg")
+	ErrNotOwner        = errors.New("lot belongs to another seller")
+)
 
 type Service interface {
-	CreateLot(sellerID int, cultivar, plantedCountry, harvestDate string, totalWeightKG int) (int, error)
-	GetLot(id int) (Lot, error)
-	UpdateLot(id int, sellerID int, harvestDate string) error
-	DeleteLot(id int, sellerID int) error
-	ListLots() ([]Lot, error)
+	CreateLot(ctx context.Context, sellerID int, cultivar, plantedCountry string, harvestDate time.Time, totalWeightKG int) (int, error)
+	GetLot(ctx context.Context, id int) (Lot, error)
+	UpdateLot(ctx context.Context, id int, sellerID int, harvestDate time.Time) error
+	DeleteLot(ctx context.Context, id int, sellerID int) error
+	ListLots(ctx context.Context) ([]Lot, error)
+	ListSellerLots(ctx context.Context, sellerID int) ([]Summary, error)
 }
 
 type service struct {
 	repo Repository
+	tx   transaction.Manager
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, tx transaction.Manager) Service {
+	return &service{repo: repo, tx: tx}
 }
 
-func (s *service) CreateLot(sellerID int, cultivar, plantedCountry, harvestDate string, totalWeightKG int) (int, error) {
+func (s *service) CreateLot(ctx context.Context, sellerID int, cultivar, plantedCountry string, harvestDate time.Time, totalWeightKG int) (int, error) {
 	if totalWeightKG < 1000 {
-		return 0, errors.New("minimum weight allowed is 1000 kg")
+		return 0, ErrTooLight
+	}
+	harvestDate, err := checkHarvestDate(harvestDate, time.Now())
+	if err != nil {
+		return 0, err
 	}
 
 	l := Lot{
@@ -31,36 +50,64 @@ func (s *service) CreateLot(sellerID int, cultivar, plantedCountry, harvestDate
 		TotalWeightKG:  totalWeightKG,
 	}
 
-	return s.repo.Create(l)
+	return s.repo.Create(ctx, l)
 }
 
-func (s *service) GetLot(id int) (Lot, error) {
-	return s.repo.GetByID(id)
-}
-
-func (s *service) UpdateLot(id int, sellerID int, harvestDate string) error {
-	l, err := s.repo.GetByID(id)
-	if err != nil {
-		return err
+// checkHarvestDate reduces d to its calendar day at midnight UTC and rejects
+// days that haven't started anywhere yet. "Today" is taken in the furthest
+// ahead time zone (UTC+14) so a seller there is never told their harvest is
+// in the future.
+func checkHarvestDate(d time.Time, now time.Time) (time.Time, error) {
+	if d.IsZero() {
+		return time.Time{}, ErrHarvestRequired
 	}
-	if l.SellerID != sellerID {
-		return errors.New("unauthorized to update this lot")
+	day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	today := now.UTC().Add(14 * time.Hour).Truncate(24 * time.Hour)
+	if day.After(today) {
+		return time.Time{}, ErrHarvestInFuture
 	}
-	l.HarvestDate = harvestDate
-	return s.repo.Update(l)
+	return day, nil
 }
 
-func (s *service) DeleteLot(id int, sellerID int) error {
-	l, err := s.repo.GetByID(id)
+func (s *service) GetLot(ctx context.Context, id int) (Lot, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *service) UpdateLot(ctx context.Context, id int, sellerID int, harvestDate time.Time) error {
+	harvestDate, err := checkHarvestDate(harvestDate, time.Now())
 	if err != nil {
 		return err
 	}
-	if l.SellerID != sellerID {
-		return errors.New("unauthorized to delete this lot")
-	}
-	return s.repo.Delete(id)
+	return s.tx.Do(ctx, transaction.RepeatableRead, func(ctx context.Context) error {
+		l, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if l.SellerID != sellerID {
+			return ErrNotOwner
+		}
+		l.HarvestDate = harvestDate
+		return s.repo.Update(ctx, l)
+	})
+}
+
+func (s *service) DeleteLot(ctx context.Context, id int, sellerID int) error {
+	return s.tx.Do(ctx, transaction.RepeatableRead, func(ctx context.Context) error {
+		l, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if l.SellerID != sellerID {
+			return ErrNotOwner
+		}
+		return s.repo.Delete(ctx, id)
+	})
+}
+
+func (s *service) ListLots(ctx context.Context) ([]Lot, error) {
+	return s.repo.List(ctx)
 }
 
-func (s *service) ListLots() ([]Lot, error) {
-	return s.repo.List()
+func (s *service) ListSellerLots(ctx context.Context, sellerID int) ([]Summary, error) {
+	return s.repo.ListBySeller(ctx, sellerID)
 }
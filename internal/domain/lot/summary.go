@@ -0,0 +1,20 @@
+package lot
+
+import (
+	"time"
+
+	"banana-auction/internal/domain/money"
+)
+
+// Summary is a seller's dashboard row: the lot plus the state of its
+// auction. The auction fields are zero when the lot has not been auctioned.
+type Summary struct {
+	Lot
+	AuctionID     *int
+	AuctionStatus string
+	// HighestBidPerKG is nil when there are no bids or they are still sealed.
+	HighestBidPerKG      *money.Amount
+	BidCount             int
+	EndsAt               *time.Time
+	TimeRemainingSeconds int64
+}
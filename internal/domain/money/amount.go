@@ -0,0 +1,227 @@
+// Package money holds exact amounts of money. Amounts are whole numbers of
+// their currency's minor unit, so adding prices or multiplying one by a
+// weight never rounds; the only rounding is where an amount is multiplied
+// by a Rate.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidAmount   = errors.New("amount must be a decimal number such as 12.50")
+	ErrTooManyDecimals = errors.New("amount has more decimal places than its currency allows")
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrTooLarge        = errors.New("amount is too large")
+	// ErrCurrencyMismatch is arithmetic on amounts in two currencies, which
+	// have to be converted explicitly first.
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+)
+
+// maxDigits bounds parsed rates so their millionths fit in an int64.
+const maxDigits = 18
+
+// maxWholeDigits bounds amounts to what the NUMERIC(18,4) columns holding
+// them can store: 14 digits before the decimal point. Every amount within it
+// fits in an int64 of minor units with room to spare.
+const maxWholeDigits = 14
+
+// Amount is an exact sum of money in one currency. The zero Amount has no
+// currency and combines with amounts in any currency, so an unset price
+// needs no special casing.
+type Amount struct {
+	minor    int64
+	currency Currency
+}
+
+// New returns minor minor units of c, e.g. New(1250, "USD") is 12.50 USD.
+func New(minor int64, c Currency) Amount {
+	return Amount{minor: minor, currency: c}
+}
+
+// Zero is nothing, in c.
+func Zero(c Currency) Amount {
+	return Amount{currency: c}
+}
+
+// Parse reads a decimal such as "12.5" as an amount in c. Trailing zeros
+// aside, s may have at most c.MinorUnits() decimal places; amounts are never
+// rounded on the way in.
+func Parse(s string, c Currency) (Amount, error) {
+	if !c.Valid() {
+		return Amount{}, fmt.Errorf("%w %q", ErrUnknownCurrency, c)
+	}
+	digits, negative := strings.CutPrefix(s, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(frac) {
+		return Amount{}, ErrInvalidAmount
+	}
+	frac = strings.TrimRight(frac, "0")
+	places := c.MinorUnits()
+	if len(frac) > places {
+		return Amount{}, fmt.Errorf("%w: %s has %d", ErrTooManyDecimals, c, places)
+	}
+	whole = strings.TrimLeft(whole, "0")
+	if len(whole) > maxWholeDigits {
+		return Amount{}, ErrTooLarge
+	}
+
+	minor, _ := strconv.ParseInt("0"+whole+frac+strings.Repeat("0", places-len(frac)), 10, 64)
+	if negative {
+		minor = -minor
+	}
+	return Amount{minor: minor, currency: c}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor is a in minor units of its currency.
+func (a Amount) Minor() int64 {
+	return a.minor
+}
+
+func (a Amount) Currency() Currency {
+	return a.currency
+}
+
+func (a Amount) IsZero() bool {
+	return a.minor == 0
+}
+
+// Sign is -1, 0 or +1 as a is negative, zero or positive.
+func (a Amount) Sign() int {
+	switch {
+	case a.minor < 0:
+		return -1
+	case a.minor > 0:
+		return 1
+	}
+	return 0
+}
+
+// Cmp compares a and b, returning -1, 0 or +1 as a is less than, equal to
+// or greater than b. Only amounts in the same currency compare meaningfully;
+// callers check currencies before comparing, as bid validation does.
+func (a Amount) Cmp(b Amount) int {
+	switch {
+	case a.minor < b.minor:
+		return -1
+	case a.minor > b.minor:
+		return 1
+	}
+	return 0
+}
+
+// Add is a plus b. It returns ErrTooLarge rather than an amount that can't
+// be stored.
+func (a Amount) Add(b Amount) (Amount, error) {
+	c, err := combine(a, b)
+	if err != nil {
+		return Amount{}, err
+	}
+	sum := new(big.Int).Add(big.NewInt(a.minor), big.NewInt(b.minor))
+	return bounded(sum, c)
+}
+
+// Sub is a minus b. Like Add it returns ErrCurrencyMismatch for amounts in
+// two currencies.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	c, err := combine(a, b)
+	if err != nil {
+		return Amount{}, err
+	}
+	return bounded(new(big.Int).Sub(big.NewInt(a.minor), big.NewInt(b.minor)), c)
+}
+
+// Mul is a times n, e.g. a price per kg times a whole number of kg. Like
+// Add it returns ErrTooLarge rather than an amount that can't be stored.
+func (a Amount) Mul(n int64) (Amount, error) {
+	product := new(big.Int).Mul(big.NewInt(a.minor), big.NewInt(n))
+	return bounded(product, a.currency)
+}
+
+// bounded returns minor minor units of c, or ErrTooLarge if that has more
+// than maxWholeDigits whole digits.
+func bounded(minor *big.Int, c Currency) (Amount, error) {
+	if new(big.Int).Abs(minor).Cmp(pow10(maxWholeDigits+c.MinorUnits())) >= 0 {
+		return Amount{}, ErrTooLarge
+	}
+	return Amount{minor: minor.Int64(), currency: c}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// Max is the larger of a and b, which like Cmp are in the same currency.
+func Max(a, b Amount) Amount {
+	if a.Cmp(b) >= 0 {
+		return a.In(b.currency)
+	}
+	return b.In(a.currency)
+}
+
+// Min is the smaller of a and b, which like Cmp are in the same currency.
+func Min(a, b Amount) Amount {
+	if a.Cmp(b) <= 0 {
+		return a.In(b.currency)
+	}
+	return b.In(a.currency)
+}
+
+// In gives an amount without a currency the currency c. It is a no-op on
+// amounts that already have one.
+func (a Amount) In(c Currency) Amount {
+	if a.currency == "" {
+		a.currency = c
+	}
+	return a
+}
+
+// combine returns the currency arithmetic on a and b results in, or
+// ErrCurrencyMismatch if they are in two currencies.
+func combine(a, b Amount) (Currency, error) {
+	switch {
+	case a.currency == b.currency || b.currency == "":
+		return a.currency, nil
+	case a.currency == "":
+		return b.currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.currency, b.currency)
+}
+
+// String formats a with exactly its currency's decimal places, e.g. "12.50".
+func (a Amount) String() string {
+	places := a.currency.MinorUnits()
+	minor := a.minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	digits := strconv.FormatInt(minor, 10)
+	if places == 0 {
+		return sign + digits
+	}
+	if len(digits) <= places {
+		digits = strings.Repeat("0", places-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-places] + "." + digits[len(digits)-places:]
+}
+
+// MarshalJSON writes a as a decimal string, so JSON clients that read
+// numbers as floating point can't lose precision.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
@@ -0,0 +1,214 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		c       Currency
+		want    int64
+		wantErr error
+	}{
+		{"12.5", "USD", 1250, nil},
+		{"12.50", "USD", 1250, nil},
+		{"0.01", "USD", 1, nil},
+		{"-3", "USD", -300, nil},
+		{"007.10", "USD", 710, nil},
+		{"1.2500", "USD", 125, nil},
+		{"150", "JPY", 150, nil},
+		{"1.234", "KWD", 1234, nil},
+		{"99999999999999.99", "USD", 9999999999999999, nil},
+		{"0.505", "USD", 0, ErrTooManyDecimals},
+		{"1.5", "JPY", 0, ErrTooManyDecimals},
+		{"100000000000000", "USD", 0, ErrTooLarge},
+		{"", "USD", 0, ErrInvalidAmount},
+		{".5", "USD", 0, ErrInvalidAmount},
+		{"1e3", "USD", 0, ErrInvalidAmount},
+		{"1,50", "USD", 0, ErrInvalidAmount},
+		{"1", "XXX", 0, ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.c)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Parse(%q, %s) error = %v, want %v", tt.in, tt.c, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (got.Minor() != tt.want || got.Currency() != tt.c) {
+			t.Errorf("Parse(%q, %s) = %d %s, want %d", tt.in, tt.c, got.Minor(), got.Currency(), tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		a    Amount
+		want string
+	}{
+		{New(1250, "USD"), "12.50"},
+		{New(5, "USD"), "0.05"},
+		{New(-5, "USD"), "-0.05"},
+		{New(0, "USD"), "0.00"},
+		{New(150, "JPY"), "150"},
+		{New(1234, "KWD"), "1.234"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	largest := New(9999999999999999, "USD")
+	tests := []struct {
+		name    string
+		got     func() (Amount, error)
+		want    Amount
+		wantErr error
+	}{
+		{"add", func() (Amount, error) { return New(150, "USD").Add(New(1, "USD")) }, New(151, "USD"), nil},
+		{"add to zero amount", func() (Amount, error) { return Amount{}.Add(New(1, "USD")) }, New(1, "USD"), nil},
+		{"add up to the limit", func() (Amount, error) { return largest.Add(New(0, "USD")) }, largest, nil},
+		{"add past the limit", func() (Amount, error) { return largest.Add(New(1, "USD")) }, Amount{}, ErrTooLarge},
+		{"add past int64", func() (Amount, error) { return New(1<<62, "USD").Add(New(1<<62, "USD")) }, Amount{}, ErrTooLarge},
+		{"mul", func() (Amount, error) { return New(65, "USD").Mul(1500) }, New(97500, "USD"), nil},
+		{"mul negative", func() (Amount, error) { return New(-65, "USD").Mul(2) }, New(-130, "USD"), nil},
+		{"add another currency", func() (Amount, error) { return New(1, "USD").Add(New(1, "EUR")) }, Amount{}, ErrCurrencyMismatch},
+		{"sub", func() (Amount, error) { return New(150, "USD").Sub(New(200, "USD")) }, New(-50, "USD"), nil},
+		{"sub another currency", func() (Amount, error) { return New(1, "USD").Sub(New(1, "EUR")) }, Amount{}, ErrCurrencyMismatch},
+		{"mul past the limit", func() (Amount, error) { return New(10000000000000, "USD").Mul(1000) }, Amount{}, ErrTooLarge},
+		{"mul past int64", func() (Amount, error) { return New(1<<40, "USD").Mul(1 << 40) }, Amount{}, ErrTooLarge},
+		{"negative past the limit", func() (Amount, error) { return New(-1<<40, "USD").Mul(1 << 40) }, Amount{}, ErrTooLarge},
+	}
+	for _, tt := range tests {
+		got, err := tt.got()
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("%s = %#v, %v; want %#v, %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	tests := []struct {
+		a, b Amount
+		want int
+	}{
+		{New(100, "USD"), New(99, "USD"), 1},
+		{New(99, "USD"), New(100, "USD"), -1},
+		{New(100, "USD"), New(100, "USD"), 0},
+		{Amount{}, New(1, "USD"), -1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Cmp(tt.b); got != tt.want {
+			t.Errorf("%v.Cmp(%v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMaxMin(t *testing.T) {
+	if got := Max(Amount{}, New(1, "USD")); got != New(1, "USD") {
+		t.Errorf("Max = %#v", got)
+	}
+	if got := Min(New(-1, "USD"), Amount{}); got != New(-1, "USD") {
+		t.Errorf("Min = %#v", got)
+	}
+	if got := Min(New(1, "USD"), Amount{}); got != Zero("USD") {
+		t.Errorf("Min with the zero amount = %#v, want it in USD", got)
+	}
+}
+
+func TestMulRate(t *testing.T) {
+	tests := []struct {
+		a       Amount
+		rate    string
+		want    Amount
+		wantErr error
+	}{
+		{New(90000, "USD"), "0.05", New(4500, "USD"), nil},
+		// 0.125 USD rounds half away from zero.
+		{New(250, "USD"), "0.05", New(13, "USD"), nil},
+		{New(-250, "USD"), "0.05", New(-13, "USD"), nil},
+		{New(249, "USD"), "0.05", New(12, "USD"), nil},
+		{New(333, "JPY"), "0.025", New(8, "JPY"), nil},
+		{New(100, "USD"), "0", New(0, "USD"), nil},
+		{New(9999999999999999, "USD"), "2", Amount{}, ErrTooLarge},
+	}
+	for _, tt := range tests {
+		r, err := ParseRate(tt.rate)
+		if err != nil {
+			t.Fatalf("ParseRate(%q): %v", tt.rate, err)
+		}
+		got, err := tt.a.MulRate(r)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("%v.MulRate(%s) = %v, %v; want %v, %v", tt.a, tt.rate, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"0.025", 25_000, false},
+		{"0.92", 920_000, false},
+		{"4100.5", 4_100_500_000, false},
+		{"1.000000", 1_000_000, false},
+		{"-0.5", -500_000, false},
+		{"0.0000001", 0, true},
+		{"1e3", 0, true},
+		{"", 0, true},
+		{"1234567890123", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRate(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRate(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.Millionths() != tt.want {
+			t.Errorf("ParseRate(%q) = %d, want %d", tt.in, got.Millionths(), tt.want)
+		}
+	}
+}
+
+func TestExchange(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       Amount
+		rate    string
+		to      Currency
+		inverse bool
+		want    Amount
+		wantErr error
+	}{
+		{"USD to EUR", New(1000, "USD"), "0.92", "EUR", false, New(920, "EUR"), nil},
+		{"USD to JPY", New(1000, "USD"), "150.5", "JPY", false, New(1505, "JPY"), nil},
+		{"JPY to USD", New(1505, "JPY"), "0.006645", "USD", false, New(1000, "USD"), nil},
+		{"rounds half away from zero", New(1, "USD"), "0.5", "EUR", false, New(1, "EUR"), nil},
+		{"EUR back to USD", New(920, "EUR"), "0.92", "USD", true, New(1000, "USD"), nil},
+		{"JPY back to USD", New(1505, "JPY"), "150.5", "USD", true, New(1000, "USD"), nil},
+		{"zero inverse rate", New(920, "EUR"), "0", "USD", true, New(0, "USD"), nil},
+		{"too large", New(9999999999999999, "USD"), "150.5", "JPY", false, Amount{}, ErrTooLarge},
+		{"too large inverse", New(99999999999999, "JPY"), "0.0001", "USD", true, Amount{}, ErrTooLarge},
+	}
+	for _, tt := range tests {
+		r, err := ParseRate(tt.rate)
+		if err != nil {
+			t.Fatalf("%s: ParseRate(%q): %v", tt.name, tt.rate, err)
+		}
+		var got Amount
+		if tt.inverse {
+			got, err = tt.a.ExchangeInverse(r, tt.to)
+		} else {
+			got, err = tt.a.Exchange(r, tt.to)
+		}
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("%s: got %v %s, %v; want %v %s, %v", tt.name, got, got.Currency(), err, tt.want, tt.want.Currency(), tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package money
+
+// Currency is an ISO 4217 currency code such as USD.
+type Currency string
+
+// minorUnits lists the currencies the marketplace trades in and how many
+// decimal places each one's minor unit has (2 for cents, 0 for yen).
+var minorUnits = map[Currency]int{
+	"AUD": 2,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CNY": 2,
+	"COP": 2,
+	"CRC": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"GTQ": 2,
+	"HNL": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MXN": 2,
+	"NZD": 2,
+	"PHP": 2,
+	"SGD": 2,
+	"USD": 2,
+	"ZAR": 2,
+}
+
+func (c Currency) Valid() bool {
+	_, ok := minorUnits[c]
+	return ok
+}
+
+// MinorUnits is the number of decimal places c's amounts have.
+func (c Currency) MinorUnits() int {
+	return minorUnits[c]
+}
+
+// Unit is the smallest amount of c, one minor unit.
+func (c Currency) Unit() Amount {
+	return Amount{minor: 1, currency: c}
+}
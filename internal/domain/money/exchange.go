@@ -0,0 +1,37 @@
+package money
+
+import "math/big"
+
+// Exchange converts a into to at r units of to per unit of a's currency,
+// rounded to the nearest minor unit of to like MulRate. 10.00 USD at 0.92
+// is 9.20 EUR; at 150.5 it is 1505 JPY. Like Mul it returns ErrTooLarge
+// rather than an amount that can't be stored.
+func (a Amount) Exchange(r Rate, to Currency) (Amount, error) {
+	num := new(big.Int).Mul(big.NewInt(a.minor), big.NewInt(r.millionths))
+	num.Mul(num, pow10(to.MinorUnits()))
+	den := new(big.Int).Mul(big.NewInt(rateScale), pow10(a.currency.MinorUnits()))
+	return bounded(divRound(num, den), to)
+}
+
+// ExchangeInverse converts a into to where r is the units of a's currency
+// per unit of to, which is how a rate stored the other way round is used.
+// A zero rate converts nothing.
+func (a Amount) ExchangeInverse(r Rate, to Currency) (Amount, error) {
+	if r.millionths == 0 {
+		return Zero(to), nil
+	}
+	num := new(big.Int).Mul(big.NewInt(a.minor), big.NewInt(rateScale))
+	num.Mul(num, pow10(to.MinorUnits()))
+	den := new(big.Int).Mul(big.NewInt(r.millionths), pow10(a.currency.MinorUnits()))
+	return bounded(divRound(num, den), to)
+}
+
+// divRound is num / den rounded to the nearest integer, halves away from
+// zero. den must be positive.
+func divRound(num, den *big.Int) *big.Int {
+	q, m := new(big.Int).QuoRem(num, den, new(big.Int))
+	if m.Abs(m).Lsh(m, 1).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+	return q
+}
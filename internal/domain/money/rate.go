@@ -0,0 +1,76 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidRate = errors.New("rate must be a decimal number with at most six decimal places")
+
+// rateScale is the number of parts a Rate's unit is divided into.
+const rateScale = 1_000_000
+
+// Rate is an exact fraction with up to six decimal places, such as a 2.5%
+// commission written 0.025.
+type Rate struct {
+	millionths int64
+}
+
+// NewRate returns the rate millionths / 1,000,000.
+func NewRate(millionths int64) Rate {
+	return Rate{millionths: millionths}
+}
+
+// ParseRate reads a decimal such as "0.025".
+func ParseRate(s string) (Rate, error) {
+	digits, negative := strings.CutPrefix(s, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	frac = strings.TrimRight(frac, "0")
+	if whole == "" || !isDigits(whole) || !isDigits(frac) || len(frac) > 6 {
+		return Rate{}, ErrInvalidRate
+	}
+	whole = strings.TrimLeft(whole, "0")
+	if len(whole) > maxDigits-6 {
+		return Rate{}, ErrInvalidRate
+	}
+	n, _ := strconv.ParseInt("0"+whole+frac+strings.Repeat("0", 6-len(frac)), 10, 64)
+	if negative {
+		n = -n
+	}
+	return Rate{millionths: n}, nil
+}
+
+// Millionths is r in millionths.
+func (r Rate) Millionths() int64 {
+	return r.millionths
+}
+
+// String formats r without trailing zeros, e.g. "0.025".
+func (r Rate) String() string {
+	n := r.millionths
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	s := strconv.FormatInt(n/rateScale, 10)
+	if frac := strings.TrimRight(strconv.FormatInt(rateScale+n%rateScale, 10)[1:], "0"); frac != "" {
+		s += "." + frac
+	}
+	return sign + s
+}
+
+// MarshalJSON writes r as a decimal string, like Amount.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(r.String())), nil
+}
+
+// MulRate is a times r, rounded to the nearest minor unit with halves
+// rounded away from zero (so 0.125 USD becomes 0.13 USD). Like Mul it
+// returns ErrTooLarge rather than an amount that can't be stored.
+func (a Amount) MulRate(r Rate) (Amount, error) {
+	p := new(big.Int).Mul(big.NewInt(a.minor), big.NewInt(r.millionths))
+	return bounded(divRound(p, big.NewInt(rateScale)), a.currency)
+}
@@ -0,0 +1,59 @@
+package settlement
+
+import (
+	"time"
+
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/money"
+)
+
+type Outcome string
+
+const (
+	OutcomeSold   Outcome = "sold"
+	OutcomeUnsold Outcome = "unsold"
+)
+
+// Settlement is the sale record written once an auction closes. Winner and
+// price fields are only set when Outcome is sold.
+//
+// A multi-unit auction has no single winner: its Allocations hold each
+// winning buyer's share, ClearingPricePerKG is the lowest accepted bid and
+// TotalWeightKG is the weight actually sold.
+//
+// Amounts are in the auction's Currency. Prices are whole minor units per
+// kg and weights whole kg, so TotalAmount and allocation amounts are exact;
+// only Commission is rounded, to the nearest minor unit with halves rounded
+// away from zero.
+type Settlement struct {
+	ID                 int
+	AuctionID          int
+	Outcome            Outcome
+	WinnerID           *int
+	WinningBidID       *int
+	Currency           money.Currency
+	ClearingPricePerKG money.Amount
+	TotalWeightKG      int
+	TotalAmount        money.Amount
+	CommissionRate     money.Rate
+	Commission         money.Amount
+	// FXRate is the rate between Currency and the marketplace's home
+	// currency when the auction settled, so the sale can be reported in the
+	// home currency at that day's rate. It is nil when the auction is priced
+	// in the home currency or no rate was on file.
+	FXRate      *fx.Rate
+	CreatedAt   time.Time
+	Allocations []Allocation
+}
+
+// Allocation is one winning bid's share of a multi-unit lot. PartialFill is
+// set when QuantityKG is below the BidQuantityKG the buyer asked for.
+type Allocation struct {
+	BidID         int
+	BuyerID       int
+	BidQuantityKG int
+	QuantityKG    int
+	PartialFill   bool
+	PricePerKG    money.Amount
+	Amount        money.Amount
+}
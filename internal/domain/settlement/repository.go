@@ -0,0 +1,17 @@
+package settlement
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrNotFound      = errors.New("settlement not found")
+	ErrAlreadyExists = errors.New("auction already settled")
+)
+
+type Repository interface {
+	// Create returns ErrAlreadyExists if the auction already has a settlement.
+	Create(ctx context.Context, s Settlement) (int, error)
+	GetByAuctionID(ctx context.Context, auctionID int) (Settlement, error)
+}
@@ -0,0 +1,278 @@
+package settlement
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/transaction"
+)
+
+var ErrAuctionNotClosed = errors.New("auction has not closed yet")
+
+type Service interface {
+	// SettleAuction writes the sale record for a closed auction and moves it
+	// to settled, or unsold when nobody bid. It is idempotent, so it can be
+	// retried after a crash or raced by several replicas.
+	SettleAuction(ctx context.Context, auctionID int) (Settlement, error)
+	GetResult(ctx context.Context, auctionID int) (Settlement, error)
+}
+
+type service struct {
+	repo           Repository
+	tx             transaction.Manager
+	auctionSvc     auction.Service
+	lotSvc         lot.Service
+	bidSvc         bid.Service
+	fxSvc          fx.Service
+	commissionRate money.Rate
+	// homeCurrency is what settlements record an exchange rate into.
+	homeCurrency money.Currency
+}
+
+func NewService(repo Repository, tx transaction.Manager, auctionSvc auction.Service, lotSvc lot.Service, bidSvc bid.Service, fxSvc fx.Service, commissionRate money.Rate, homeCurrency money.Currency) Service {
+	return &service{
+		repo:           repo,
+		tx:             tx,
+		auctionSvc:     auctionSvc,
+		lotSvc:         lotSvc,
+		bidSvc:         bidSvc,
+		fxSvc:          fxSvc,
+		commissionRate: commissionRate,
+		homeCurrency:   homeCurrency,
+	}
+}
+
+func (s *service) SettleAuction(ctx context.Context, auctionID int) (Settlement, error) {
+	// The settlement and the auction's move to settled or unsold commit
+	// together, and nothing the price is computed from can change meanwhile.
+	var st Settlement
+	err := s.tx.Do(ctx, transaction.Serializable, func(ctx context.Context) error {
+		var err error
+		st, err = s.settle(ctx, auctionID)
+		return err
+	})
+	if errors.Is(err, ErrAlreadyExists) {
+		// Another replica got here first; finish its status change if needed.
+		return s.finish(ctx, auctionID)
+	}
+	return st, err
+}
+
+func (s *service) settle(ctx context.Context, auctionID int) (Settlement, error) {
+	a, err := s.auctionSvc.GetAuction(ctx, auctionID)
+	if err != nil {
+		return Settlement{}, err
+	}
+	if a.Status != auction.StatusClosed {
+		if existing, err := s.repo.GetByAuctionID(ctx, auctionID); err == nil {
+			return existing, nil
+		}
+		return Settlement{}, ErrAuctionNotClosed
+	}
+
+	l, err := s.lotSvc.GetLot(ctx, a.LotID)
+	if err != nil {
+		return Settlement{}, err
+	}
+
+	zero := money.Zero(a.Currency)
+	st := Settlement{
+		AuctionID:          auctionID,
+		Outcome:            OutcomeUnsold,
+		Currency:           a.Currency,
+		ClearingPricePerKG: zero,
+		TotalWeightKG:      l.TotalWeightKG,
+		TotalAmount:        zero,
+		CommissionRate:     s.commissionRate,
+		Commission:         zero,
+	}
+	if a.Currency != s.homeCurrency {
+		rate, err := s.fxSvc.Find(ctx, a.Currency, s.homeCurrency)
+		if err != nil && !errors.Is(err, fx.ErrNotFound) {
+			return Settlement{}, err
+		}
+		if err == nil {
+			st.FXRate = &rate
+		}
+	}
+	next := auction.StatusUnsold
+	if a.Type == auction.TypeMultiUnit {
+		bids, err := s.bidSvc.ListBids(ctx, auctionID)
+		if err != nil {
+			return Settlement{}, err
+		}
+		// Bids under the reserve don't take part in the allocation.
+		var eligible []bid.Bid
+		for _, b := range bids {
+			if a.ReserveMet(b.BidPricePerKG) {
+				eligible = append(eligible, b)
+			}
+		}
+		st.TotalWeightKG = 0
+		st.Allocations, err = allocate(eligible, l.TotalWeightKG, a.Clearing)
+		if err != nil {
+			return Settlement{}, err
+		}
+		for _, al := range st.Allocations {
+			st.TotalWeightKG += al.QuantityKG
+			if st.TotalAmount, err = st.TotalAmount.Add(al.Amount); err != nil {
+				return Settlement{}, err
+			}
+		}
+		if len(st.Allocations) > 0 {
+			// Bids are allocated highest first, so the last one is the marginal bid.
+			st.ClearingPricePerKG = st.Allocations[len(st.Allocations)-1].PricePerKG
+			st.Outcome = OutcomeSold
+			if st.Commission, err = st.TotalAmount.MulRate(s.commissionRate); err != nil {
+				return Settlement{}, err
+			}
+			next = auction.StatusSettled
+		}
+	} else if a.WinningBidID != nil {
+		b, err := s.bidSvc.GetBid(ctx, *a.WinningBidID)
+		if err != nil {
+			return Settlement{}, err
+		}
+		if !a.ReserveMet(b.BidPricePerKG) {
+			return s.save(ctx, st, auction.StatusUnsold)
+		}
+		price, err := s.clearingPrice(ctx, a, b)
+		if err != nil {
+			return Settlement{}, err
+		}
+		st.Outcome = OutcomeSold
+		st.WinnerID = &b.BuyerID
+		st.WinningBidID = &b.ID
+		st.ClearingPricePerKG = price
+		if st.TotalAmount, err = price.Mul(int64(l.TotalWeightKG)); err != nil {
+			return Settlement{}, err
+		}
+		if st.Commission, err = st.TotalAmount.MulRate(s.commissionRate); err != nil {
+			return Settlement{}, err
+		}
+		next = auction.StatusSettled
+	}
+	return s.save(ctx, st, next)
+}
+
+// save stores st and moves the auction to next.
+func (s *service) save(ctx context.Context, st Settlement, next auction.Status) (Settlement, error) {
+	id, err := s.repo.Create(ctx, st)
+	if err != nil {
+		return Settlement{}, err
+	}
+	st.ID = id
+	return st, s.moveTo(ctx, st.AuctionID, next)
+}
+
+// finish loads the auction's existing settlement and makes sure the auction
+// is in the status it calls for.
+func (s *service) finish(ctx context.Context, auctionID int) (Settlement, error) {
+	st, err := s.repo.GetByAuctionID(ctx, auctionID)
+	if err != nil {
+		return Settlement{}, err
+	}
+	next := auction.StatusUnsold
+	if st.Outcome == OutcomeSold {
+		next = auction.StatusSettled
+	}
+	if err := s.moveTo(ctx, auctionID, next); err != nil {
+		return Settlement{}, err
+	}
+	return st, nil
+}
+
+// moveTo moves the auction to settled or unsold, ignoring an auction that
+// has already left closed.
+func (s *service) moveTo(ctx context.Context, auctionID int, next auction.Status) error {
+	var err error
+	if next == auction.StatusSettled {
+		err = s.auctionSvc.SettleAuction(ctx, auctionID)
+	} else {
+		err = s.auctionSvc.MarkUnsold(ctx, auctionID)
+	}
+	if err != nil && !errors.Is(err, auction.ErrInvalidTransition) {
+		return err
+	}
+	return nil
+}
+
+// clearingPrice is what the winner pays per kg. Second-price (Vickrey)
+// auctions charge the highest losing bid, or the initial price or reserve
+// when nobody else bid that much; every other format charges the winning bid.
+func (s *service) clearingPrice(ctx context.Context, a auction.Auction, winning bid.Bid) (money.Amount, error) {
+	if a.Type != auction.TypeSealedSecondPrice {
+		return winning.BidPricePerKG, nil
+	}
+
+	bids, err := s.bidSvc.ListBids(ctx, a.ID)
+	if err != nil {
+		return money.Amount{}, err
+	}
+	price := money.Max(a.InitialPricePerKG, a.ReservePricePerKG)
+	for _, b := range bids {
+		if b.ID != winning.ID && b.BuyerID != winning.BuyerID {
+			price = money.Max(price, b.BidPricePerKG)
+		}
+	}
+	return money.Min(price, winning.BidPricePerKG), nil
+}
+
+// allocate fills the lot from the highest bid down, earliest bid first on
+// equal prices, until weightKG runs out. The last bid filled may get only
+// part of its quantity. Under uniform clearing everyone pays the lowest
+// accepted price; under pay-as-bid each winner pays their own. An amount
+// too large to store is money.ErrTooLarge.
+func allocate(bids []bid.Bid, weightKG int, clearing auction.Clearing) ([]Allocation, error) {
+	sorted := make([]bid.Bid, len(bids))
+	copy(sorted, bids)
+	sort.Slice(sorted, func(i, j int) bool {
+		if c := sorted[i].BidPricePerKG.Cmp(sorted[j].BidPricePerKG); c != 0 {
+			return c > 0
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var allocations []Allocation
+	remaining := weightKG
+	for _, b := range sorted {
+		if remaining == 0 {
+			break
+		}
+		if b.QuantityKG <= 0 {
+			continue
+		}
+		quantity := min(b.QuantityKG, remaining)
+		remaining -= quantity
+		allocations = append(allocations, Allocation{
+			BidID:         b.ID,
+			BuyerID:       b.BuyerID,
+			BidQuantityKG: b.QuantityKG,
+			QuantityKG:    quantity,
+			PartialFill:   quantity < b.QuantityKG,
+			PricePerKG:    b.BidPricePerKG,
+		})
+	}
+
+	for i := range allocations {
+		if clearing == auction.ClearingUniform {
+			allocations[i].PricePerKG = allocations[len(allocations)-1].PricePerKG
+		}
+		amount, err := allocations[i].PricePerKG.Mul(int64(allocations[i].QuantityKG))
+		if err != nil {
+			return nil, err
+		}
+		allocations[i].Amount = amount
+	}
+	return allocations, nil
+}
+
+func (s *service) GetResult(ctx context.Context, auctionID int) (Settlement, error) {
+	return s.repo.GetByAuctionID(ctx, auctionID)
+}
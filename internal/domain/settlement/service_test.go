@@ -0,0 +1,475 @@
+package settlement
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/transaction"
+)
+
+func usd(minor int64) money.Amount { return money.New(minor, "USD") }
+
+func rate(t *testing.T, s string) money.Rate {
+	t.Helper()
+	r, err := money.ParseRate(s)
+	if err != nil {
+		t.Fatalf("ParseRate(%q): %v", s, err)
+	}
+	return r
+}
+
+func TestAllocate(t *testing.T) {
+	b := func(id, buyerID int, price int64, quantity int) bid.Bid {
+		return bid.Bid{ID: id, BuyerID: buyerID, BidPricePerKG: usd(price), QuantityKG: quantity}
+	}
+	al := func(bidID, buyerID, asked, got int, price, amount int64) Allocation {
+		return Allocation{
+			BidID: bidID, BuyerID: buyerID, BidQuantityKG: asked, QuantityKG: got,
+			PartialFill: got < asked, PricePerKG: usd(price), Amount: usd(amount),
+		}
+	}
+	tests := []struct {
+		name     string
+		bids     []bid.Bid
+		weightKG int
+		clearing auction.Clearing
+		want     []Allocation
+		wantErr  error
+	}{
+		{
+			name:     "uniform with a partial fill",
+			bids:     []bid.Bid{b(1, 1, 150, 400), b(2, 2, 200, 500), b(3, 3, 120, 300)},
+			weightKG: 1000,
+			clearing: auction.ClearingUniform,
+			want:     []Allocation{al(2, 2, 500, 500, 120, 60000), al(1, 1, 400, 400, 120, 48000), al(3, 3, 300, 100, 120, 12000)},
+		},
+		{
+			name:     "pay as bid",
+			bids:     []bid.Bid{b(1, 1, 150, 400), b(2, 2, 200, 500), b(3, 3, 120, 300)},
+			weightKG: 1000,
+			clearing: auction.ClearingPayAsBid,
+			want:     []Allocation{al(2, 2, 500, 500, 200, 100000), al(1, 1, 400, 400, 150, 60000), al(3, 3, 300, 100, 120, 12000)},
+		},
+		{
+			name:     "equal prices go to the earliest bid",
+			bids:     []bid.Bid{b(5, 1, 150, 600), b(4, 2, 150, 600)},
+			weightKG: 1000,
+			clearing: auction.ClearingPayAsBid,
+			want:     []Allocation{al(4, 2, 600, 600, 150, 90000), al(5, 1, 600, 400, 150, 60000)},
+		},
+		{
+			name:     "weight left over",
+			bids:     []bid.Bid{b(1, 1, 150, 100)},
+			weightKG: 1000,
+			clearing: auction.ClearingUniform,
+			want:     []Allocation{al(1, 1, 100, 100, 150, 15000)},
+		},
+		{
+			name:     "losing bids get nothing",
+			bids:     []bid.Bid{b(1, 1, 150, 1000), b(2, 2, 140, 10)},
+			weightKG: 1000,
+			clearing: auction.ClearingUniform,
+			want:     []Allocation{al(1, 1, 1000, 1000, 150, 150000)},
+		},
+		{
+			name:     "no bids",
+			weightKG: 1000,
+			clearing: auction.ClearingUniform,
+		},
+		{
+			name:     "amount too large",
+			bids:     []bid.Bid{b(1, 1, 9999999999999999, 2)},
+			weightKG: 2,
+			clearing: auction.ClearingPayAsBid,
+			wantErr:  money.ErrTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		got, err := allocate(tt.bids, tt.weightKG, tt.clearing)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: allocation %d = %+v, want %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+type fakeAuctions struct {
+	auction.Service
+	a auction.Auction
+}
+
+func (f *fakeAuctions) GetAuction(_ context.Context, id int) (auction.Auction, error) {
+	return f.a, nil
+}
+
+func (f *fakeAuctions) moveTo(next auction.Status) error {
+	if !f.a.Status.CanTransitionTo(next) {
+		return auction.ErrInvalidTransition
+	}
+	f.a.Status = next
+	return nil
+}
+
+func (f *fakeAuctions) SettleAuction(context.Context, int) error {
+	return f.moveTo(auction.StatusSettled)
+}
+
+func (f *fakeAuctions) MarkUnsold(context.Context, int) error {
+	return f.moveTo(auction.StatusUnsold)
+}
+
+type fakeLots struct {
+	lot.Service
+	weightKG int
+}
+
+func (f fakeLots) GetLot(_ context.Context, id int) (lot.Lot, error) {
+	return lot.Lot{ID: id, TotalWeightKG: f.weightKG}, nil
+}
+
+type fakeBids struct {
+	bid.Service
+	bids []bid.Bid
+}
+
+func (f fakeBids) GetBid(_ context.Context, id int) (bid.Bid, error) {
+	for _, b := range f.bids {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return bid.Bid{}, bid.ErrNotFound
+}
+
+func (f fakeBids) ListBids(context.Context, int) ([]bid.Bid, error) {
+	return f.bids, nil
+}
+
+type fakeFX struct {
+	fx.Service
+	rates []fx.Rate
+}
+
+func (f fakeFX) Find(_ context.Context, from, to money.Currency) (fx.Rate, error) {
+	for _, r := range f.rates {
+		if r.Converts(from, to) {
+			return r, nil
+		}
+	}
+	return fx.Rate{}, fx.ErrNotFound
+}
+
+type fakeRepo struct {
+	settlements []Settlement
+}
+
+func (r *fakeRepo) Create(_ context.Context, s Settlement) (int, error) {
+	for _, existing := range r.settlements {
+		if existing.AuctionID == s.AuctionID {
+			return 0, ErrAlreadyExists
+		}
+	}
+	s.ID = len(r.settlements) + 1
+	r.settlements = append(r.settlements, s)
+	return s.ID, nil
+}
+
+func (r *fakeRepo) GetByAuctionID(_ context.Context, auctionID int) (Settlement, error) {
+	for _, s := range r.settlements {
+		if s.AuctionID == auctionID {
+			return s, nil
+		}
+	}
+	return Settlement{}, ErrNotFound
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Do(ctx context.Context, _ transaction.Isolation, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestSettleAuction(t *testing.T) {
+	winner := func(id int) *int { return &id }
+	closed := func(typ auction.Type, winningBidID *int) auction.Auction {
+		return auction.Auction{
+			ID:                1,
+			LotID:             1,
+			Currency:          "USD",
+			InitialPricePerKG: usd(100),
+			Type:              typ,
+			Status:            auction.StatusClosed,
+			WinningBidID:      winningBidID,
+		}
+	}
+	withReserve := func(a auction.Auction, reserve int64) auction.Auction {
+		a.ReservePricePerKG = usd(reserve)
+		return a
+	}
+	sealed := []bid.Bid{
+		{ID: 1, BuyerID: 1, BidPricePerKG: usd(200)},
+		{ID: 2, BuyerID: 2, BidPricePerKG: usd(170)},
+		{ID: 3, BuyerID: 3, BidPricePerKG: usd(130)},
+	}
+	multiUnit := closed(auction.TypeMultiUnit, nil)
+	multiUnit.Clearing = auction.ClearingUniform
+	euros := closed(auction.TypeEnglish, winner(1))
+	euros.Currency = "EUR"
+	euros.InitialPricePerKG = money.New(100, "EUR")
+	usdEUR := fx.Rate{Base: "USD", Quote: "EUR", Rate: rate(t, "0.92")}
+
+	tests := []struct {
+		name         string
+		a            auction.Auction
+		bids         []bid.Bid
+		weightKG     int
+		rates        []fx.Rate
+		wantStatus   auction.Status
+		wantWinner   int
+		wantPrice    money.Amount
+		wantTotal    money.Amount
+		wantComm     money.Amount
+		wantWeightKG int
+		wantFX       bool
+	}{
+		{
+			name:       "english",
+			a:          closed(auction.TypeEnglish, winner(1)),
+			bids:       []bid.Bid{{ID: 1, BuyerID: 7, BidPricePerKG: usd(150)}},
+			weightKG:   1000,
+			wantStatus: auction.StatusSettled, wantWinner: 7,
+			wantPrice: usd(150), wantTotal: usd(150000), wantComm: usd(7500), wantWeightKG: 1000,
+		},
+		{
+			name:       "commission rounds half away from zero",
+			a:          closed(auction.TypeEnglish, winner(1)),
+			bids:       []bid.Bid{{ID: 1, BuyerID: 7, BidPricePerKG: usd(253)}},
+			weightKG:   10,
+			wantStatus: auction.StatusSettled, wantWinner: 7,
+			wantPrice: usd(253), wantTotal: usd(2530), wantComm: usd(127), wantWeightKG: 10,
+		},
+		{
+			name:       "reserve met exactly",
+			a:          withReserve(closed(auction.TypeEnglish, winner(1)), 150),
+			bids:       []bid.Bid{{ID: 1, BuyerID: 7, BidPricePerKG: usd(150)}},
+			weightKG:   1000,
+			wantStatus: auction.StatusSettled, wantWinner: 7,
+			wantPrice: usd(150), wantTotal: usd(150000), wantComm: usd(7500), wantWeightKG: 1000,
+		},
+		{
+			name:       "reserve not met",
+			a:          withReserve(closed(auction.TypeEnglish, winner(1)), 151),
+			bids:       []bid.Bid{{ID: 1, BuyerID: 7, BidPricePerKG: usd(150)}},
+			weightKG:   1000,
+			wantStatus: auction.StatusUnsold, wantWeightKG: 1000,
+		},
+		{
+			name:       "no bids",
+			a:          closed(auction.TypeEnglish, nil),
+			weightKG:   1000,
+			wantStatus: auction.StatusUnsold, wantWeightKG: 1000,
+		},
+		{
+			name:       "vickrey pays the second price",
+			a:          closed(auction.TypeSealedSecondPrice, winner(1)),
+			bids:       sealed,
+			weightKG:   10,
+			wantStatus: auction.StatusSettled, wantWinner: 1,
+			wantPrice: usd(170), wantTotal: usd(1700), wantComm: usd(85), wantWeightKG: 10,
+		},
+		{
+			name:       "vickrey pays at least the reserve",
+			a:          withReserve(closed(auction.TypeSealedSecondPrice, winner(1)), 180),
+			bids:       sealed,
+			weightKG:   10,
+			wantStatus: auction.StatusSettled, wantWinner: 1,
+			wantPrice: usd(180), wantTotal: usd(1800), wantComm: usd(90), wantWeightKG: 10,
+		},
+		{
+			name:       "vickrey with a single bid pays the initial price",
+			a:          closed(auction.TypeSealedSecondPrice, winner(1)),
+			bids:       sealed[:1],
+			weightKG:   10,
+			wantStatus: auction.StatusSettled, wantWinner: 1,
+			wantPrice: usd(100), wantTotal: usd(1000), wantComm: usd(50), wantWeightKG: 10,
+		},
+		{
+			name: "vickrey ties pay the winning bid",
+			a:    closed(auction.TypeSealedSecondPrice, winner(1)),
+			bids: []bid.Bid{
+				{ID: 1, BuyerID: 1, BidPricePerKG: usd(200)},
+				{ID: 2, BuyerID: 2, BidPricePerKG: usd(200)},
+			},
+			weightKG:   10,
+			wantStatus: auction.StatusSettled, wantWinner: 1,
+			wantPrice: usd(200), wantTotal: usd(2000), wantComm: usd(100), wantWeightKG: 10,
+		},
+		{
+			name:       "first price pays the winning bid",
+			a:          closed(auction.TypeSealedFirstPrice, winner(1)),
+			bids:       sealed,
+			weightKG:   10,
+			wantStatus: auction.StatusSettled, wantWinner: 1,
+			wantPrice: usd(200), wantTotal: usd(2000), wantComm: usd(100), wantWeightKG: 10,
+		},
+		{
+			name: "multi-unit sells part of the lot",
+			a:    multiUnit,
+			bids: []bid.Bid{
+				{ID: 1, BuyerID: 1, BidPricePerKG: usd(150), QuantityKG: 300},
+				{ID: 2, BuyerID: 2, BidPricePerKG: usd(130), QuantityKG: 300},
+			},
+			weightKG:   1000,
+			wantStatus: auction.StatusSettled,
+			wantPrice:  usd(130), wantTotal: usd(78000), wantComm: usd(3900), wantWeightKG: 600,
+		},
+		{
+			name: "multi-unit leaves out bids under the reserve",
+			a:    withReserve(multiUnit, 120),
+			bids: []bid.Bid{
+				{ID: 1, BuyerID: 1, BidPricePerKG: usd(150), QuantityKG: 600},
+				{ID: 2, BuyerID: 2, BidPricePerKG: usd(130), QuantityKG: 600},
+				{ID: 3, BuyerID: 3, BidPricePerKG: usd(110), QuantityKG: 600},
+			},
+			weightKG:   1000,
+			wantStatus: auction.StatusSettled,
+			wantPrice:  usd(130), wantTotal: usd(130000), wantComm: usd(6500), wantWeightKG: 1000,
+		},
+		{
+			name:       "multi-unit with every bid under the reserve",
+			a:          withReserve(multiUnit, 200),
+			bids:       []bid.Bid{{ID: 1, BuyerID: 1, BidPricePerKG: usd(150), QuantityKG: 600}},
+			weightKG:   1000,
+			wantStatus: auction.StatusUnsold,
+		},
+		{
+			name:       "multi-unit with no bids",
+			a:          multiUnit,
+			weightKG:   1000,
+			wantStatus: auction.StatusUnsold,
+		},
+		{
+			name:       "records the rate into the home currency",
+			a:          euros,
+			bids:       []bid.Bid{{ID: 1, BuyerID: 7, BidPricePerKG: money.New(150, "EUR")}},
+			weightKG:   10,
+			rates:      []fx.Rate{usdEUR},
+			wantStatus: auction.StatusSettled, wantWinner: 7,
+			wantPrice: money.New(150, "EUR"), wantTotal: money.New(1500, "EUR"), wantComm: money.New(75, "EUR"),
+			wantWeightKG: 10, wantFX: true,
+		},
+		{
+			name:       "settles without a rate on file",
+			a:          euros,
+			bids:       []bid.Bid{{ID: 1, BuyerID: 7, BidPricePerKG: money.New(150, "EUR")}},
+			weightKG:   10,
+			wantStatus: auction.StatusSettled, wantWinner: 7,
+			wantPrice: money.New(150, "EUR"), wantTotal: money.New(1500, "EUR"), wantComm: money.New(75, "EUR"),
+			wantWeightKG: 10,
+		},
+	}
+	for _, tt := range tests {
+		auctions := &fakeAuctions{a: tt.a}
+		repo := &fakeRepo{}
+		svc := NewService(repo, fakeTx{}, auctions, fakeLots{weightKG: tt.weightKG},
+			fakeBids{bids: tt.bids}, fakeFX{rates: tt.rates}, rate(t, "0.05"), "USD")
+
+		st, err := svc.SettleAuction(context.Background(), 1)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if auctions.a.Status != tt.wantStatus {
+			t.Errorf("%s: auction is %s, want %s", tt.name, auctions.a.Status, tt.wantStatus)
+		}
+		if len(repo.settlements) != 1 || repo.settlements[0].ID != st.ID {
+			t.Errorf("%s: stored %+v, want the returned settlement", tt.name, repo.settlements)
+		}
+		if sold := tt.wantStatus == auction.StatusSettled; (st.Outcome == OutcomeSold) != sold {
+			t.Errorf("%s: outcome = %s", tt.name, st.Outcome)
+		}
+		if (st.WinnerID == nil && tt.wantWinner != 0) || (st.WinnerID != nil && *st.WinnerID != tt.wantWinner) {
+			t.Errorf("%s: winner = %v, want %d", tt.name, st.WinnerID, tt.wantWinner)
+		}
+		zero := money.Zero(tt.a.Currency)
+		wantPrice, wantTotal, wantComm := tt.wantPrice, tt.wantTotal, tt.wantComm
+		if tt.wantStatus == auction.StatusUnsold {
+			wantPrice, wantTotal, wantComm = zero, zero, zero
+		}
+		if st.ClearingPricePerKG != wantPrice || st.TotalAmount != wantTotal || st.Commission != wantComm {
+			t.Errorf("%s: price %v, total %v, commission %v; want %v, %v, %v", tt.name,
+				st.ClearingPricePerKG, st.TotalAmount, st.Commission, wantPrice, wantTotal, wantComm)
+		}
+		if st.TotalWeightKG != tt.wantWeightKG {
+			t.Errorf("%s: weight = %d, want %d", tt.name, st.TotalWeightKG, tt.wantWeightKG)
+		}
+		if (st.FXRate != nil) != tt.wantFX {
+			t.Errorf("%s: FXRate = %+v, want one: %v", tt.name, st.FXRate, tt.wantFX)
+		}
+	}
+}
+
+func TestSettleAuctionTwice(t *testing.T) {
+	id := 1
+	auctions := &fakeAuctions{a: auction.Auction{
+		ID: 1, Currency: "USD", InitialPricePerKG: usd(100), Type: auction.TypeEnglish,
+		Status: auction.StatusClosed, WinningBidID: &id,
+	}}
+	repo := &fakeRepo{}
+	svc := NewService(repo, fakeTx{}, auctions, fakeLots{weightKG: 10},
+		fakeBids{bids: []bid.Bid{{ID: 1, BuyerID: 7, BidPricePerKG: usd(150)}}}, fakeFX{}, rate(t, "0.05"), "USD")
+
+	first, err := svc.SettleAuction(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.SettleAuction(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ID != first.ID || len(repo.settlements) != 1 {
+		t.Errorf("settling again gave %+v and stored %d settlements", second, len(repo.settlements))
+	}
+}
+
+func TestSettleAuctionErrors(t *testing.T) {
+	id := 1
+	tests := []struct {
+		name     string
+		a        auction.Auction
+		weightKG int
+		want     error
+	}{
+		{"still live", auction.Auction{ID: 1, Currency: "USD", Status: auction.StatusLive}, 10, ErrAuctionNotClosed},
+		{"total too large", auction.Auction{
+			ID: 1, Currency: "USD", Type: auction.TypeEnglish, Status: auction.StatusClosed, WinningBidID: &id,
+		}, 2, money.ErrTooLarge},
+	}
+	for _, tt := range tests {
+		auctions := &fakeAuctions{a: tt.a}
+		repo := &fakeRepo{}
+		svc := NewService(repo, fakeTx{}, auctions, fakeLots{weightKG: tt.weightKG},
+			fakeBids{bids: []bid.Bid{{ID: 1, BuyerID: 7, BidPricePerKG: usd(9999999999999999)}}}, fakeFX{}, rate(t, "0.05"), "USD")
+		if _, err := svc.SettleAuction(context.Background(), 1); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+		if len(repo.settlements) != 0 || auctions.a.Status != tt.a.Status {
+			t.Errorf("%s: failed settlement changed state", tt.name)
+		}
+	}
+}
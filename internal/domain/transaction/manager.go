@@ -0,0 +1,23 @@
+package transaction
+
+import "context"
+
+// Isolation is the isolation level a unit of work runs at.
+type Isolation int
+
+const (
+	ReadCommitted Isolation = iota
+	RepeatableRead
+	Serializable
+)
+
+// Manager runs units of work that span several repositories. Repository
+// calls made with the ctx handed to fn take part in the transaction.
+type Manager interface {
+	// Do runs fn in one transaction at iso and commits when fn returns nil.
+	// When the database aborts the transaction on a serialization failure
+	// or deadlock, fn is run again from the start, so it must not have side
+	// effects outside the database; publish events after Do returns.
+	// A Do inside another joins the outer transaction at its isolation.
+	Do(ctx context.Context, iso Isolation, fn func(ctx context.Context) error) error
+}
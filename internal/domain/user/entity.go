@@ -1,5 +1,10 @@
 package user
 
+const (
+	RoleSeller = "seller"
+	RoleBuyer  = "buyer"
+)
+
 type User struct {
 	ID           int
 	Username     string
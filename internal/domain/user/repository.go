@@ -1,7 +1,18 @@
 package user
 
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrNotFound      = errors.New("user not found")
+	ErrUsernameTaken = errors.New("username already exists")
+)
+
 type Repository interface {
-	Create(u User) (int, error)
-	GetByUsername(username string) (User, error)
-	GetByID(id int) (User, error)
+	// Create returns ErrUsernameTaken if the username is in use.
+	Create(ctx context.Context, u User) (int, error)
+	GetByUsername(ctx context.Context, username string) (User, error)
+	GetByID(ctx context.Context, id int) (User, error)
 }
\ No newline at end of file
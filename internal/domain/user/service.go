@@ -1,25 +1,61 @@
 package user
 
 import (
+	"banana-auction/config"
 	"banana-auction/internal/infrastructure/utils"
+	"context"
 	"errors"
+	"strings"
+	"time"
 )
 
+var (
+	ErrInvalidRole        = errors.New("role must be seller or buyer")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
+
+// MissingFieldsError lists the signup fields that were left empty.
+type MissingFieldsError struct {
+	Fields []string
+}
+
+func (e *MissingFieldsError) Error() string {
+	return strings.Join(e.Fields, ", ") + " required"
+}
+
 type Service interface {
-	Register(username, password, name, role string) (int, error)
-	Login(username, password string) (string, error)
-	GetUser(id int) (User, error)
+	Register(ctx context.Context, username, password, name, role string) (int, error)
+	Login(ctx context.Context, username, password string) (Tokens, error)
+	Refresh(ctx context.Context, refreshToken string) (Tokens, error)
+	Logout(ctx context.Context, refreshToken string) error
+	GetUser(ctx context.Context, id int) (User, error)
 }
 
 type service struct {
-	repo Repository
+	repo      Repository
+	tokenRepo TokenRepository
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, tokenRepo TokenRepository) Service {
+	return &service{repo: repo, tokenRepo: tokenRepo}
 }
 
-func (s *service) Register(username, password, name, role string) (int, error) {
+func (s *service) Register(ctx context.Context, username, password, name, role string) (int, error) {
+	var missing []string
+	for _, f := range []struct{ name, value string }{
+		{"username", username}, {"password", password}, {"name", name}, {"role", role},
+	} {
+		if f.value == "" {
+			missing = append(missing, f.name)
+		}
+	}
+	if missing != nil {
+		return 0, &MissingFieldsError{Fields: missing}
+	}
+	if role != RoleSeller && role != RoleBuyer {
+		return 0, ErrInvalidRole
+	}
+
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
 		return 0, err
@@ -32,22 +68,110 @@ func (s *service) Register(username, password, name, role string) (int, error) {
 		Role:         role,
 	}
 
-	return s.repo.Create(u)
+	return s.repo.Create(ctx, u)
 }
 
-func (s *service) Login(username, password string) (string, error) {
-	u, err := s.repo.GetByUsername(username)
+func (s *service) Login(ctx context.Context, username, password string) (Tokens, error) {
+	// Unknown users get the same error as wrong passwords, so logins can't
+	// be used to find out who has an account.
+	u, err := s.repo.GetByUsername(ctx, username)
+	if errors.Is(err, ErrNotFound) {
+		return Tokens{}, ErrInvalidCredentials
+	}
 	if err != nil {
-		return "", err
+		return Tokens{}, err
 	}
 
 	if !utils.CheckPassword(password, u.PasswordHash) {
-		return "", errors.New("invalid password")
+		return Tokens{}, ErrInvalidCredentials
+	}
+
+	familyID, err := utils.NewTokenID()
+	if err != nil {
+		return Tokens{}, err
+	}
+	return s.issueTokens(ctx, u, familyID)
+}
+
+func (s *service) Refresh(ctx context.Context, refreshToken string) (Tokens, error) {
+	claims, err := utils.ParseRefreshJWT(refreshToken)
+	if err != nil {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+
+	stored, err := s.tokenRepo.GetRefreshToken(ctx, claims.TokenID)
+	if err != nil {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+
+	ok, err := s.tokenRepo.MarkRefreshTokenUsed(ctx, stored.ID)
+	if err != nil {
+		return Tokens{}, err
+	}
+	if !ok {
+		// The token was already rotated, so someone is replaying it.
+		if err := s.tokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return Tokens{}, err
+		}
+		return Tokens{}, ErrRefreshTokenReused
+	}
+
+	u, err := s.repo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return Tokens{}, err
+	}
+	return s.issueTokens(ctx, u, stored.FamilyID)
+}
+
+func (s *service) Logout(ctx context.Context, refreshToken string) error {
+	claims, err := utils.ParseRefreshJWT(refreshToken)
+	if err != nil {
+		return ErrInvalidRefreshToken
+	}
+	return s.tokenRepo.RevokeFamily(ctx, claims.FamilyID)
+}
+
+// issueTokens creates a new access token and a new refresh token in familyID.
+func (s *service) issueTokens(ctx context.Context, u User, familyID string) (Tokens, error) {
+	cfg := config.GetConfig()
+
+	accessToken, err := utils.GenerateJWT(u.ID, u.Role)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	tokenID, err := utils.NewTokenID()
+	if err != nil {
+		return Tokens{}, err
+	}
+	rt := RefreshToken{
+		ID:        tokenID,
+		FamilyID:  familyID,
+		UserID:    u.ID,
+		ExpiresAt: time.Now().Add(cfg.RefreshTokenTTL),
+	}
+	if err := s.tokenRepo.CreateRefreshToken(ctx, rt); err != nil {
+		return Tokens{}, err
+	}
+	refreshToken, err := utils.GenerateRefreshJWT(utils.RefreshClaims{
+		TokenID:  rt.ID,
+		FamilyID: rt.FamilyID,
+		UserID:   rt.UserID,
+	}, rt.ExpiresAt)
+	if err != nil {
+		return Tokens{}, err
 	}
 
-	return utils.GenerateJWT(u.ID)
+	return Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    cfg.AccessTokenTTL,
+	}, nil
 }
 
-func (s *service) GetUser(id int) (User, error) {
-	return s.repo.GetByID(id)
+func (s *service) GetUser(ctx context.Context, id int) (User, error) {
+	return s.repo.GetByID(ctx, id)
 }
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, please log in again")
+)
+
+// Tokens is what a successful login or refresh hands back to the client.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// RefreshToken is the server-side record of an issued refresh token. Every
+// refresh rotates the token within the same family; presenting a token that
+// was already used revokes the whole family.
+type RefreshToken struct {
+	ID        string
+	FamilyID  string
+	UserID    int
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, t RefreshToken) error
+	GetRefreshToken(ctx context.Context, id string) (RefreshToken, error)
+	// MarkRefreshTokenUsed reports false if the token was already used or revoked.
+	MarkRefreshTokenUsed(ctx context.Context, id string) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+}
@@ -0,0 +1,1100 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"banana-auction/config"
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/catalog"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/settlement"
+	"banana-auction/internal/domain/transaction"
+	"banana-auction/internal/domain/user"
+	"banana-auction/internal/infrastructure/persistence/memory"
+	"banana-auction/internal/infrastructure/persistence/postgres"
+	"banana-auction/internal/infrastructure/persistence/sqlite"
+)
+
+// The conformance suite pins down the behaviour every backend must share,
+// so the in-memory repositories can stand in for Postgres in tests.
+
+func TestMemoryConformance(t *testing.T) {
+	runConformance(t, func(t *testing.T) *Repositories {
+		return Memory(memory.NewStore())
+	})
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	runConformance(t, func(t *testing.T) *Repositories {
+		cfg := &config.Config{
+			DbDriver:   config.DriverSQLite,
+			SqlitePath: filepath.Join(t.TempDir(), "conformance.db"),
+			DBTimeout:  10 * time.Second,
+		}
+		if err := sqlite.InitDB(cfg); err != nil {
+			t.Fatalf("init database: %v", err)
+		}
+		db := sqlite.GetDB()
+		t.Cleanup(func() { db.Close() })
+		return SQLite()
+	})
+}
+
+// TestPostgresConformance runs against the database named by TEST_DB_NAME.
+// Every test truncates all tables, so point it at a throwaway database.
+func TestPostgresConformance(t *testing.T) {
+	name := os.Getenv("TEST_DB_NAME")
+	if name == "" {
+		t.Skip("TEST_DB_NAME not set")
+	}
+	port, err := strconv.Atoi(envOr("TEST_DB_PORT", "5432"))
+	if err != nil {
+		t.Fatalf("TEST_DB_PORT: %v", err)
+	}
+	cfg := &config.Config{
+		DbDriver:   config.DriverPostgres,
+		DbHost:     envOr("TEST_DB_HOST", "localhost"),
+		DbPort:     port,
+		DbUser:     envOr("TEST_DB_USER", "postgres"),
+		DbPassword: os.Getenv("TEST_DB_PASSWORD"),
+		DbName:     name,
+		DBTimeout:  10 * time.Second,
+	}
+	if err := postgres.InitDB(cfg); err != nil {
+		t.Fatalf("init database: %v", err)
+	}
+
+	runConformance(t, func(t *testing.T) *Repositories {
+		_, err := postgres.GetDB().Exec(`TRUNCATE users, refresh_tokens, lots, auctions, bids, proxy_bids,
+			settlements, settlement_allocations, fx_rates RESTART IDENTITY CASCADE`)
+		if err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		return Postgres()
+	})
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// runConformance runs every check against fresh, empty repositories from open.
+func runConformance(t *testing.T, open func(t *testing.T) *Repositories) {
+	tests := []struct {
+		name string
+		fn   func(f *fixture)
+	}{
+		{"users", testUsers},
+		{"tokens", testTokens},
+		{"lots", testLots},
+		{"lot delete cascade", testLotDeleteCascade},
+		{"auctions", testAuctions},
+		{"auction close", testAuctionClose},
+		{"auction seller summaries", testAuctionListBySeller},
+		{"bids", testBids},
+		{"auction lock", testAuctionLock},
+		{"proxy registration", testProxyRegistration},
+		{"concurrent bids", testConcurrentBids},
+		{"buyer summaries", testBidListByBuyer},
+		{"settlements", testSettlements},
+		{"catalog", testCatalog},
+		{"fx rates", testFXRates},
+		{"transactions", testTransactions},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(&fixture{t: t, ctx: context.Background(), r: open(t)})
+		})
+	}
+}
+
+type fixture struct {
+	t   *testing.T
+	ctx context.Context
+	r   *Repositories
+}
+
+func (f *fixture) must(err error) {
+	f.t.Helper()
+	if err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+func (f *fixture) wantErr(err, target error) {
+	f.t.Helper()
+	if !errors.Is(err, target) {
+		f.t.Fatalf("got error %v, want %v", err, target)
+	}
+}
+
+func (f *fixture) user(username, role string) int {
+	f.t.Helper()
+	id, err := f.r.Users.Create(f.ctx, user.User{Username: username, PasswordHash: "hash", Name: username, Role: role})
+	f.must(err)
+	return id
+}
+
+// day is a calendar date as the services hold one, at midnight UTC.
+func day(s string) time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func usd(s string) money.Amount {
+	a, err := money.Parse(s, "USD")
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func (f *fixture) lot(sellerID int) int {
+	f.t.Helper()
+	id, err := f.r.Lots.Create(f.ctx, lot.Lot{
+		SellerID:       sellerID,
+		Cultivar:       "Cavendish",
+		PlantedCountry: "Ecuador",
+		HarvestDate:    day("2025-01-15"),
+		TotalWeightKG:  1000,
+	})
+	f.must(err)
+	return id
+}
+
+// auction creates an english auction on lotID with status, running from
+// start for one day.
+func (f *fixture) auction(lotID int, status auction.Status, start time.Time) int {
+	f.t.Helper()
+	id, err := f.r.Auctions.Create(f.ctx, auction.Auction{
+		LotID:             lotID,
+		StartDate:         start.UTC().Truncate(time.Second),
+		DurationDays:      1,
+		Currency:          "USD",
+		InitialPricePerKG: usd("1"),
+		Type:              auction.TypeEnglish,
+		Status:            status,
+	})
+	f.must(err)
+	return id
+}
+
+func (f *fixture) bid(auctionID, buyerID int, price string) int {
+	f.t.Helper()
+	id, err := f.r.Bids.Create(f.ctx, bid.Bid{AuctionID: auctionID, BuyerID: buyerID, BidPricePerKG: usd(price)})
+	f.must(err)
+	return id
+}
+
+func (f *fixture) getAuction(id int) auction.Auction {
+	f.t.Helper()
+	a, err := f.r.Auctions.GetByID(f.ctx, id)
+	f.must(err)
+	return a
+}
+
+func testUsers(f *fixture) {
+	id := f.user("alice", user.RoleSeller)
+
+	u, err := f.r.Users.GetByID(f.ctx, id)
+	f.must(err)
+	if u.Username != "alice" || u.Role != user.RoleSeller || u.PasswordHash != "hash" {
+		f.t.Fatalf("GetByID = %+v", u)
+	}
+	u, err = f.r.Users.GetByUsername(f.ctx, "alice")
+	f.must(err)
+	if u.ID != id {
+		f.t.Fatalf("GetByUsername ID = %d, want %d", u.ID, id)
+	}
+
+	_, err = f.r.Users.Create(f.ctx, user.User{Username: "alice", Role: user.RoleBuyer})
+	f.wantErr(err, user.ErrUsernameTaken)
+
+	_, err = f.r.Users.GetByID(f.ctx, id+100)
+	f.wantErr(err, user.ErrNotFound)
+	_, err = f.r.Users.GetByUsername(f.ctx, "bob")
+	f.wantErr(err, user.ErrNotFound)
+}
+
+func testTokens(f *fixture) {
+	userID := f.user("alice", user.RoleBuyer)
+	for _, id := range []string{"t1", "t2"} {
+		f.must(f.r.Tokens.CreateRefreshToken(f.ctx, user.RefreshToken{
+			ID: id, FamilyID: "fam", UserID: userID, ExpiresAt: time.Now().Add(time.Hour),
+		}))
+	}
+
+	ok, err := f.r.Tokens.MarkRefreshTokenUsed(f.ctx, "t1")
+	f.must(err)
+	if !ok {
+		f.t.Fatal("first MarkRefreshTokenUsed = false")
+	}
+	if ok, _ := f.r.Tokens.MarkRefreshTokenUsed(f.ctx, "t1"); ok {
+		f.t.Fatal("second MarkRefreshTokenUsed = true")
+	}
+
+	f.must(f.r.Tokens.RevokeFamily(f.ctx, "fam"))
+	t2, err := f.r.Tokens.GetRefreshToken(f.ctx, "t2")
+	f.must(err)
+	if t2.RevokedAt == nil || t2.UsedAt != nil || t2.UserID != userID {
+		f.t.Fatalf("GetRefreshToken = %+v", t2)
+	}
+	if ok, _ := f.r.Tokens.MarkRefreshTokenUsed(f.ctx, "t2"); ok {
+		f.t.Fatal("MarkRefreshTokenUsed on a revoked token = true")
+	}
+
+	_, err = f.r.Tokens.GetRefreshToken(f.ctx, "missing")
+	if !errors.Is(err, user.ErrInvalidRefreshToken) {
+		f.t.Fatalf("GetRefreshToken(missing) error = %v", err)
+	}
+}
+
+func testLots(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	other := f.user("other", user.RoleSeller)
+
+	if _, err := f.r.Lots.Create(f.ctx, lot.Lot{SellerID: seller + other, TotalWeightKG: 1000}); err == nil {
+		f.t.Fatal("Create with an unknown seller succeeded")
+	}
+
+	id := f.lot(seller)
+	l, err := f.r.Lots.GetByID(f.ctx, id)
+	f.must(err)
+	if l.SellerID != seller || l.Cultivar != "Cavendish" || l.TotalWeightKG != 1000 {
+		f.t.Fatalf("GetByID = %+v", l)
+	}
+
+	// Update only applies when the seller matches.
+	f.must(f.r.Lots.Update(f.ctx, lot.Lot{ID: id, SellerID: other, HarvestDate: day("2025-02-01")}))
+	if l, _ := f.r.Lots.GetByID(f.ctx, id); !l.HarvestDate.Equal(day("2025-01-15")) {
+		f.t.Fatalf("HarvestDate after another seller's update = %s", l.HarvestDate)
+	}
+	f.must(f.r.Lots.Update(f.ctx, lot.Lot{ID: id, SellerID: seller, HarvestDate: day("2025-02-01")}))
+	if l, _ := f.r.Lots.GetByID(f.ctx, id); !l.HarvestDate.Equal(day("2025-02-01")) {
+		f.t.Fatalf("HarvestDate after update = %s", l.HarvestDate)
+	}
+
+	_, err = f.r.Lots.GetByID(f.ctx, id+100)
+	f.wantErr(err, lot.ErrNotFound)
+
+	second := f.lot(seller)
+	f.lot(other)
+	lots, err := f.r.Lots.List(f.ctx)
+	f.must(err)
+	if len(lots) != 3 {
+		f.t.Fatalf("List returned %d lots, want 3", len(lots))
+	}
+
+	auctionID := f.auction(second, auction.StatusLive, time.Now().Add(-time.Hour))
+	f.bid(auctionID, other, "2")
+	summaries, err := f.r.Lots.ListBySeller(f.ctx, seller)
+	f.must(err)
+	if len(summaries) != 2 || summaries[0].ID != second || summaries[1].ID != id {
+		f.t.Fatalf("ListBySeller = %+v, want lots %d and %d newest first", summaries, second, id)
+	}
+	s := summaries[0]
+	if s.AuctionID == nil || *s.AuctionID != auctionID || s.AuctionStatus != string(auction.StatusLive) ||
+		s.BidCount != 1 || s.HighestBidPerKG == nil || *s.HighestBidPerKG != usd("2") || s.EndsAt == nil ||
+		s.TimeRemainingSeconds <= 0 {
+		f.t.Fatalf("ListBySeller with auction = %+v", s)
+	}
+	if s := summaries[1]; s.AuctionID != nil || s.AuctionStatus != "" || s.EndsAt != nil || s.BidCount != 0 {
+		f.t.Fatalf("ListBySeller without auction = %+v", s)
+	}
+}
+
+func testLotDeleteCascade(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	buyer := f.user("buyer", user.RoleBuyer)
+	lotID := f.lot(seller)
+	keptLot := f.lot(seller)
+	auctionID := f.auction(lotID, auction.StatusLive, time.Now().Add(-time.Hour))
+	keptAuction := f.auction(keptLot, auction.StatusLive, time.Now().Add(-time.Hour))
+	bidID := f.bid(auctionID, buyer, "2")
+	keptBid := f.bid(keptAuction, buyer, "2")
+	f.must(f.r.Bids.WithAuctionLock(f.ctx, auctionID, func(l bid.Locked) error {
+		_, err := l.SaveProxy(bid.ProxyBid{AuctionID: auctionID, BuyerID: buyer, MaxPricePerKG: usd("5")})
+		return err
+	}))
+
+	// A live auction keeps its lot.
+	f.wantErr(f.r.Lots.Delete(f.ctx, lotID), lot.ErrAuctionStarted)
+	if _, err := f.r.Bids.GetByID(f.ctx, bidID); err != nil {
+		f.t.Fatalf("bid after refused delete: %v", err)
+	}
+
+	// Once it is cancelled the lot goes with the auction, its bids and proxies.
+	if ok, err := f.r.Auctions.Cancel(f.ctx, auctionID, "frost"); err != nil || !ok {
+		f.t.Fatalf("Cancel = %v, %v", ok, err)
+	}
+	f.must(f.r.Lots.Delete(f.ctx, lotID))
+
+	_, err := f.r.Lots.GetByID(f.ctx, lotID)
+	f.wantErr(err, lot.ErrNotFound)
+	_, err = f.r.Auctions.GetByID(f.ctx, auctionID)
+	f.wantErr(err, auction.ErrNotFound)
+	_, err = f.r.Bids.GetByID(f.ctx, bidID)
+	f.wantErr(err, bid.ErrNotFound)
+	if _, err := f.r.Bids.GetProxy(f.ctx, auctionID, buyer); !errors.Is(err, bid.ErrProxyNotFound) {
+		f.t.Fatalf("GetProxy after delete error = %v", err)
+	}
+
+	// Other lots are untouched.
+	if _, err := f.r.Bids.GetByID(f.ctx, keptBid); err != nil {
+		f.t.Fatalf("bid on another lot: %v", err)
+	}
+	if a := f.getAuction(keptAuction); a.LotID != keptLot {
+		f.t.Fatalf("auction on another lot = %+v", a)
+	}
+
+	// Deleting a lot that doesn't exist is not an error.
+	f.must(f.r.Lots.Delete(f.ctx, lotID))
+
+	// A scheduled auction has to be cancelled first.
+	scheduledLot := f.lot(seller)
+	scheduledAuction := f.auction(scheduledLot, auction.StatusScheduled, time.Now().Add(time.Hour))
+	f.wantErr(f.r.Lots.Delete(f.ctx, scheduledLot), lot.ErrHasAuction)
+	f.getAuction(scheduledAuction)
+
+	// A settlement is never deleted, so neither is its lot.
+	f.must(f.r.Bids.WithAuctionLock(f.ctx, keptAuction, func(l bid.Locked) error {
+		_, err := l.Close(keptBid)
+		return err
+	}))
+	_, err = f.r.Settlements.Create(f.ctx, settlement.Settlement{
+		AuctionID: keptAuction, Outcome: settlement.OutcomeSold, WinnerID: &buyer, WinningBidID: &keptBid,
+		Currency: "USD", ClearingPricePerKG: usd("2"), TotalWeightKG: 1000, TotalAmount: usd("2000"),
+	})
+	f.must(err)
+	f.wantErr(f.r.Lots.Delete(f.ctx, keptLot), lot.ErrSettled)
+	if _, err := f.r.Settlements.GetByAuctionID(f.ctx, keptAuction); err != nil {
+		f.t.Fatalf("settlement after refused delete: %v", err)
+	}
+
+	// A lot that was never auctioned simply goes.
+	bareLot := f.lot(seller)
+	f.must(f.r.Lots.Delete(f.ctx, bareLot))
+	_, err = f.r.Lots.GetByID(f.ctx, bareLot)
+	f.wantErr(err, lot.ErrNotFound)
+}
+
+func testAuctions(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	lotID := f.lot(seller)
+
+	winning := 99
+	in := auction.Auction{
+		LotID:             lotID,
+		StartDate:         day("2030-01-01"),
+		DurationDays:      3,
+		Currency:          "USD",
+		InitialPricePerKG: usd("1.5"),
+		ReservePricePerKG: usd("2"),
+		BuyNowPricePerKG:  usd("4"),
+		Dutch:             auction.Dutch{FloorPricePerKG: usd("0"), DecrementPerKG: usd("0")},
+		Type:              auction.TypeEnglish,
+		Status:            auction.StatusScheduled,
+		WinningBidID:      &winning,
+		SoftClose:         auction.SoftClose{WindowMinutes: 5, ExtensionMinutes: 2, MaxExtensionMinutes: 10},
+		ExtensionMinutes:  7,
+		CancelReason:      "ignored",
+	}
+	id, err := f.r.Auctions.Create(f.ctx, in)
+	f.must(err)
+
+	want := in
+	want.ID = id
+	want.WinningBidID = nil
+	want.ExtensionMinutes = 0
+	want.CancelReason = ""
+	if got := f.getAuction(id); !sameAuction(got, want) {
+		f.t.Fatalf("GetByID = %+v, want %+v", got, want)
+	}
+	_, err = f.r.Auctions.GetByID(f.ctx, id+100)
+	f.wantErr(err, auction.ErrNotFound)
+
+	if _, err := f.r.Auctions.Create(f.ctx, auction.Auction{LotID: lotID + 100, Type: auction.TypeEnglish}); err == nil {
+		f.t.Fatal("Create with an unknown lot succeeded")
+	}
+
+	exists, err := f.r.Auctions.ExistsForLot(f.ctx, lotID)
+	f.must(err)
+	if !exists {
+		f.t.Fatal("ExistsForLot = false")
+	}
+	if exists, _ := f.r.Auctions.ExistsForLot(f.ctx, lotID+100); exists {
+		f.t.Fatal("ExistsForLot(unknown lot) = true")
+	}
+
+	// Update is a compare-and-swap on the status.
+	edit := want
+	edit.DurationDays = 5
+	edit.Status = auction.StatusLive
+	if ok, err := f.r.Auctions.Update(f.ctx, edit); err != nil || ok {
+		f.t.Fatalf("Update with a stale status = %v, %v", ok, err)
+	}
+	edit.Status = auction.StatusScheduled
+	if ok, err := f.r.Auctions.Update(f.ctx, edit); err != nil || !ok {
+		f.t.Fatalf("Update = %v, %v", ok, err)
+	}
+	if got := f.getAuction(id); got.DurationDays != 5 {
+		f.t.Fatalf("DurationDays after Update = %d", got.DurationDays)
+	}
+
+	if ok, _ := f.r.Auctions.Transition(f.ctx, id, auction.StatusLive, auction.StatusClosed); ok {
+		f.t.Fatal("Transition from the wrong status = true")
+	}
+	if ok, err := f.r.Auctions.Transition(f.ctx, id, auction.StatusScheduled, auction.StatusLive); err != nil || !ok {
+		f.t.Fatalf("Transition = %v, %v", ok, err)
+	}
+
+	live, err := f.r.Auctions.ListByStatus(f.ctx, auction.StatusLive, auction.StatusClosed)
+	f.must(err)
+	if len(live) != 1 || live[0].ID != id {
+		f.t.Fatalf("ListByStatus = %+v", live)
+	}
+	if none, _ := f.r.Auctions.ListByStatus(f.ctx, auction.StatusScheduled); len(none) != 0 {
+		f.t.Fatalf("ListByStatus(scheduled) = %+v", none)
+	}
+
+	if ok, err := f.r.Auctions.Cancel(f.ctx, id, "frost"); err != nil || !ok {
+		f.t.Fatalf("Cancel = %v, %v", ok, err)
+	}
+	if got := f.getAuction(id); got.Status != auction.StatusCancelled || got.CancelReason != "frost" {
+		f.t.Fatalf("after Cancel = %+v", got)
+	}
+	if ok, _ := f.r.Auctions.Cancel(f.ctx, id, "again"); ok {
+		f.t.Fatal("Cancel of a cancelled auction = true")
+	}
+
+	all, err := f.r.Auctions.List(f.ctx)
+	f.must(err)
+	if len(all) != 1 {
+		f.t.Fatalf("List returned %d auctions, want 1", len(all))
+	}
+}
+
+// sameAuction compares auctions by value, following WinningBidID.
+func sameAuction(a, b auction.Auction) bool {
+	if (a.WinningBidID == nil) != (b.WinningBidID == nil) ||
+		(a.WinningBidID != nil && *a.WinningBidID != *b.WinningBidID) {
+		return false
+	}
+	if !a.StartDate.Equal(b.StartDate) {
+		return false
+	}
+	a.WinningBidID, b.WinningBidID = nil, nil
+	a.StartDate, b.StartDate = time.Time{}, time.Time{}
+	return a == b
+}
+
+func testAuctionClose(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	b1 := f.user("b1", user.RoleBuyer)
+	b2 := f.user("b2", user.RoleBuyer)
+
+	ended := f.auction(f.lot(seller), auction.StatusLive, time.Now().AddDate(0, 0, -2))
+	f.bid(ended, b1, "10")
+	first := f.bid(ended, b2, "12")
+	f.bid(ended, b1, "12")
+
+	ok, err := f.r.Auctions.Close(f.ctx, ended)
+	f.must(err)
+	if !ok {
+		f.t.Fatal("Close of an ended live auction = false")
+	}
+	a := f.getAuction(ended)
+	if a.Status != auction.StatusClosed || a.WinningBidID == nil || *a.WinningBidID != first {
+		f.t.Fatalf("after Close = %+v, want winner %d", a, first)
+	}
+	if ok, _ := f.r.Auctions.Close(f.ctx, ended); ok {
+		f.t.Fatal("second Close = true")
+	}
+
+	running := f.auction(f.lot(seller), auction.StatusLive, time.Now().Add(-time.Hour))
+	if ok, _ := f.r.Auctions.Close(f.ctx, running); ok {
+		f.t.Fatal("Close before the end = true")
+	}
+
+	empty := f.auction(f.lot(seller), auction.StatusLive, time.Now().AddDate(0, 0, -2))
+	if ok, _ := f.r.Auctions.Close(f.ctx, empty); !ok {
+		f.t.Fatal("Close without bids = false")
+	}
+	if a := f.getAuction(empty); a.WinningBidID != nil {
+		f.t.Fatalf("winner without bids = %d", *a.WinningBidID)
+	}
+}
+
+func testAuctionListBySeller(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	other := f.user("other", user.RoleSeller)
+	buyer := f.user("buyer", user.RoleBuyer)
+
+	english := f.auction(f.lot(seller), auction.StatusLive, time.Now().Add(-time.Hour))
+	sealed, err := f.r.Auctions.Create(f.ctx, auction.Auction{
+		LotID:             f.lot(seller),
+		StartDate:         time.Now().Add(-time.Hour).UTC().Truncate(time.Second),
+		DurationDays:      1,
+		Currency:          "USD",
+		InitialPricePerKG: usd("1"),
+		Type:              auction.TypeSealedFirstPrice,
+		Status:            auction.StatusLive,
+	})
+	f.must(err)
+	f.auction(f.lot(other), auction.StatusLive, time.Now())
+	f.bid(english, buyer, "3")
+	f.bid(english, buyer, "4")
+	f.bid(sealed, buyer, "5")
+
+	summaries, err := f.r.Auctions.ListBySeller(f.ctx, seller)
+	f.must(err)
+	if len(summaries) != 2 || summaries[0].ID != sealed || summaries[1].ID != english {
+		f.t.Fatalf("ListBySeller = %+v, want auctions %d and %d newest first", summaries, sealed, english)
+	}
+	if s := summaries[0]; s.HighestBidPerKG != nil || s.BidCount != 1 {
+		f.t.Fatalf("sealed summary = %+v, want the highest bid hidden", s)
+	}
+	s := summaries[1]
+	if s.HighestBidPerKG == nil || *s.HighestBidPerKG != usd("4") || s.BidCount != 2 || s.Cultivar != "Cavendish" ||
+		s.TotalWeightKG != 1000 || s.TimeRemainingSeconds <= 0 {
+		f.t.Fatalf("english summary = %+v", s)
+	}
+	if end := s.Auction.EndTime(); !s.EndsAt.Equal(end) {
+		f.t.Fatalf("EndsAt = %v, want %v", s.EndsAt, end)
+	}
+}
+
+func testBids(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	buyer := f.user("buyer", user.RoleBuyer)
+	auctionID := f.auction(f.lot(seller), auction.StatusLive, time.Now())
+
+	highest, err := f.r.Bids.Highest(f.ctx, auctionID)
+	f.must(err)
+	if highest != nil {
+		f.t.Fatalf("Highest without bids = %+v", highest)
+	}
+
+	if _, err := f.r.Bids.Create(f.ctx, bid.Bid{AuctionID: auctionID, BuyerID: buyer + 100, BidPricePerKG: usd("1")}); err == nil {
+		f.t.Fatal("Create with an unknown buyer succeeded")
+	}
+
+	f.bid(auctionID, buyer, "2")
+	first := f.bid(auctionID, buyer, "3")
+	last := f.bid(auctionID, buyer, "3")
+	highest, err = f.r.Bids.Highest(f.ctx, auctionID)
+	f.must(err)
+	if highest == nil || highest.ID != first {
+		f.t.Fatalf("Highest = %+v, want the earliest of the equal bids (%d)", highest, first)
+	}
+
+	f.must(f.r.Bids.Update(f.ctx, bid.Bid{ID: last, BidPricePerKG: usd("3.5")}))
+	b, err := f.r.Bids.GetByID(f.ctx, last)
+	f.must(err)
+	if b.BidPricePerKG != usd("3.5") || b.AuctionID != auctionID || b.BuyerID != buyer || b.Proxy {
+		f.t.Fatalf("GetByID = %+v", b)
+	}
+
+	f.must(f.r.Bids.Delete(f.ctx, last))
+	_, err = f.r.Bids.GetByID(f.ctx, last)
+	f.wantErr(err, bid.ErrNotFound)
+
+	bids, err := f.r.Bids.ListByAuctionID(f.ctx, auctionID)
+	f.must(err)
+	if len(bids) != 2 {
+		f.t.Fatalf("ListByAuctionID returned %d bids, want 2", len(bids))
+	}
+
+	if _, err := f.r.Bids.GetProxy(f.ctx, auctionID, buyer); !errors.Is(err, bid.ErrProxyNotFound) {
+		f.t.Fatalf("GetProxy without a proxy error = %v", err)
+	}
+}
+
+func testAuctionLock(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	buyer := f.user("buyer", user.RoleBuyer)
+	auctionID := f.auction(f.lot(seller), auction.StatusLive, time.Now())
+
+	err := f.r.Bids.WithAuctionLock(f.ctx, auctionID+100, func(l bid.Locked) error { return nil })
+	if !errors.Is(err, bid.ErrAuctionNotFound) {
+		f.t.Fatalf("WithAuctionLock on a missing auction error = %v", err)
+	}
+
+	// An error from fn rolls back everything it wrote.
+	errRollback := errors.New("rollback")
+	err = f.r.Bids.WithAuctionLock(f.ctx, auctionID, func(l bid.Locked) error {
+		if _, err := l.CreateBid(bid.Bid{AuctionID: auctionID, BuyerID: buyer, BidPricePerKG: usd("2")}); err != nil {
+			return err
+		}
+		if _, err := l.Extend(5); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		f.t.Fatalf("WithAuctionLock error = %v", err)
+	}
+	if bids, _ := f.r.Bids.ListByAuctionID(f.ctx, auctionID); len(bids) != 0 {
+		f.t.Fatalf("bids after rollback = %+v", bids)
+	}
+	if a := f.getAuction(auctionID); a.ExtensionMinutes != 0 {
+		f.t.Fatalf("ExtensionMinutes after rollback = %d", a.ExtensionMinutes)
+	}
+
+	var proxyID, bidID int
+	f.must(f.r.Bids.WithAuctionLock(f.ctx, auctionID, func(l bid.Locked) error {
+		if l.Auction().ID != auctionID {
+			f.t.Errorf("Auction().ID = %d", l.Auction().ID)
+		}
+		weight, err := l.LotWeightKG()
+		if err != nil || weight != 1000 {
+			f.t.Errorf("LotWeightKG = %d, %v", weight, err)
+		}
+		if proxyID, err = l.SaveProxy(bid.ProxyBid{AuctionID: auctionID, BuyerID: buyer, MaxPricePerKG: usd("5")}); err != nil {
+			return err
+		}
+		again, err := l.SaveProxy(bid.ProxyBid{AuctionID: auctionID, BuyerID: buyer, MaxPricePerKG: usd("6")})
+		if err != nil {
+			return err
+		}
+		if again != proxyID {
+			f.t.Errorf("SaveProxy for the same buyer returned %d, want %d", again, proxyID)
+		}
+		proxies, err := l.Proxies()
+		if err != nil {
+			return err
+		}
+		if len(proxies) != 1 || proxies[0].MaxPricePerKG != usd("6") {
+			f.t.Errorf("Proxies = %+v", proxies)
+		}
+
+		if bidID, err = l.CreateBid(bid.Bid{AuctionID: auctionID, BuyerID: buyer, BidPricePerKG: usd("2"), Proxy: true}); err != nil {
+			return err
+		}
+		mine, err := l.BuyerBid(buyer)
+		if err != nil {
+			return err
+		}
+		if mine == nil || mine.ID != bidID || !mine.Proxy {
+			f.t.Errorf("BuyerBid = %+v", mine)
+		}
+		extended, err := l.Extend(3)
+		if err != nil {
+			return err
+		}
+		if extended.ExtensionMinutes != 3 || l.Auction().ExtensionMinutes != 3 {
+			f.t.Errorf("Extend = %+v", extended)
+		}
+		return nil
+	}))
+	if a := f.getAuction(auctionID); a.ExtensionMinutes != 3 {
+		f.t.Fatalf("ExtensionMinutes = %d, want 3", a.ExtensionMinutes)
+	}
+	p, err := f.r.Bids.GetProxy(f.ctx, auctionID, buyer)
+	f.must(err)
+	if p.ID != proxyID || p.MaxPricePerKG != usd("6") || p.RegisteredAt.IsZero() {
+		f.t.Fatalf("GetProxy = %+v", p)
+	}
+
+	f.must(f.r.Bids.WithAuctionLock(f.ctx, auctionID, func(l bid.Locked) error {
+		if err := l.DeleteBid(bidID); err != nil {
+			return err
+		}
+		if mine, _ := l.BuyerBid(buyer); mine != nil {
+			f.t.Errorf("BuyerBid after DeleteBid = %+v", mine)
+		}
+		id, err := l.CreateBid(bid.Bid{AuctionID: auctionID, BuyerID: buyer, BidPricePerKG: usd("4")})
+		if err != nil {
+			return err
+		}
+		closed, err := l.Close(id)
+		if err != nil {
+			return err
+		}
+		if closed.Status != auction.StatusClosed || closed.WinningBidID == nil || *closed.WinningBidID != id {
+			f.t.Errorf("Close = %+v", closed)
+		}
+		bidID = id
+		return nil
+	}))
+	if a := f.getAuction(auctionID); a.Status != auction.StatusClosed || a.WinningBidID == nil || *a.WinningBidID != bidID {
+		f.t.Fatalf("after Locked.Close = %+v", a)
+	}
+
+	// Only a live auction can be closed.
+	scheduled := f.auction(f.lot(seller), auction.StatusScheduled, time.Now().Add(-time.Hour))
+	err = f.r.Bids.WithAuctionLock(f.ctx, scheduled, func(l bid.Locked) error {
+		id, err := l.CreateBid(bid.Bid{AuctionID: scheduled, BuyerID: buyer, BidPricePerKG: usd("4")})
+		if err != nil {
+			return err
+		}
+		_, err = l.Close(id)
+		return err
+	})
+	f.wantErr(err, auction.ErrInvalidTransition)
+	if a := f.getAuction(scheduled); a.Status != auction.StatusScheduled || a.WinningBidID != nil {
+		f.t.Fatalf("after refused Locked.Close = %+v", a)
+	}
+}
+
+// testProxyRegistration checks that resubmitting the same maximum keeps the
+// proxy's place in the queue and only a new maximum counts as registering
+// again.
+func testProxyRegistration(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	buyer := f.user("buyer", user.RoleBuyer)
+	auctionID := f.auction(f.lot(seller), auction.StatusLive, time.Now().Add(-time.Hour))
+	save := func(max string) time.Time {
+		f.t.Helper()
+		f.must(f.r.Bids.WithAuctionLock(f.ctx, auctionID, func(l bid.Locked) error {
+			_, err := l.SaveProxy(bid.ProxyBid{AuctionID: auctionID, BuyerID: buyer, MaxPricePerKG: usd(max)})
+			return err
+		}))
+		p, err := f.r.Bids.GetProxy(f.ctx, auctionID, buyer)
+		f.must(err)
+		return p.RegisteredAt
+	}
+
+	tests := []struct {
+		name    string
+		max     string
+		renewed bool
+	}{
+		{"first registration", "5", true},
+		{"same maximum", "5", false},
+		{"raised maximum", "6", true},
+		{"same maximum after raise", "6", false},
+	}
+	var registered time.Time
+	for _, tt := range tests {
+		time.Sleep(5 * time.Millisecond)
+		at := save(tt.max)
+		if renewed := !at.Equal(registered); renewed != tt.renewed {
+			f.t.Fatalf("%s: RegisteredAt %v -> %v, want renewed = %v", tt.name, registered, at, tt.renewed)
+		}
+		registered = at
+	}
+}
+
+// testConcurrentBids has many goroutines each raise the highest bid by one
+// under the auction lock. Without mutual exclusion some would read the same
+// highest bid and place equal bids.
+func testConcurrentBids(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	buyer := f.user("buyer", user.RoleBuyer)
+	auctionID := f.auction(f.lot(seller), auction.StatusLive, time.Now())
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- f.r.Bids.WithAuctionLock(f.ctx, auctionID, func(l bid.Locked) error {
+				highest, err := l.Highest()
+				if err != nil {
+					return err
+				}
+				price := usd("1")
+				if highest != nil {
+					if price, err = highest.BidPricePerKG.Add(usd("1")); err != nil {
+						return err
+					}
+				}
+				_, err = l.CreateBid(bid.Bid{AuctionID: auctionID, BuyerID: buyer, BidPricePerKG: price})
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		f.must(err)
+	}
+
+	bids, err := f.r.Bids.ListByAuctionID(f.ctx, auctionID)
+	f.must(err)
+	seen := map[money.Amount]bool{}
+	for _, b := range bids {
+		if seen[b.BidPricePerKG] {
+			f.t.Fatalf("two bids at %v", b.BidPricePerKG)
+		}
+		seen[b.BidPricePerKG] = true
+	}
+	if len(bids) != n || !seen[money.New(n*100, "USD")] {
+		f.t.Fatalf("got %d bids, want %d ending at %d", len(bids), n, n)
+	}
+}
+
+func testBidListByBuyer(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	alice := f.user("alice", user.RoleBuyer)
+	bob := f.user("bob", user.RoleBuyer)
+
+	leading := f.auction(f.lot(seller), auction.StatusLive, time.Now().Add(-time.Hour))
+	outbid := f.auction(f.lot(seller), auction.StatusLive, time.Now().Add(-2*time.Hour))
+	won := f.auction(f.lot(seller), auction.StatusLive, time.Now().AddDate(0, 0, -3))
+
+	f.bid(leading, alice, "2")
+	best := f.bid(leading, alice, "3")
+	f.bid(leading, bob, "2.5")
+	f.bid(outbid, alice, "2")
+	f.bid(outbid, bob, "4")
+	winning := f.bid(won, alice, "5")
+	if ok, err := f.r.Auctions.Close(f.ctx, won); err != nil || !ok {
+		f.t.Fatalf("Close = %v, %v", ok, err)
+	}
+	_, err := f.r.Settlements.Create(f.ctx, settlement.Settlement{
+		AuctionID: won, Outcome: settlement.OutcomeSold, WinnerID: &alice, WinningBidID: &winning,
+		Currency: "USD", ClearingPricePerKG: usd("5"), TotalWeightKG: 1000, TotalAmount: usd("5000"),
+	})
+	f.must(err)
+	if ok, err := f.r.Auctions.Transition(f.ctx, won, auction.StatusClosed, auction.StatusSettled); err != nil || !ok {
+		f.t.Fatalf("Transition = %v, %v", ok, err)
+	}
+
+	summaries, err := f.r.Bids.ListByBuyer(f.ctx, alice)
+	f.must(err)
+	if len(summaries) != 3 || summaries[0].AuctionID != leading || summaries[1].AuctionID != outbid ||
+		summaries[2].AuctionID != won {
+		f.t.Fatalf("ListByBuyer = %+v, want latest ending first", summaries)
+	}
+	s := summaries[0]
+	if s.BidCount != 2 || s.BestBid.ID != best || s.Leading == nil || !*s.Leading || s.Won {
+		f.t.Fatalf("leading summary = %+v", s)
+	}
+	if s := summaries[1]; s.Leading == nil || *s.Leading {
+		f.t.Fatalf("outbid summary = %+v", s)
+	}
+	if s := summaries[2]; s.Leading != nil || !s.Won || s.AuctionStatus != auction.StatusSettled {
+		f.t.Fatalf("won summary = %+v", s)
+	}
+
+	if ok, err := f.r.Auctions.Cancel(f.ctx, outbid, "frost"); err != nil || !ok {
+		f.t.Fatalf("Cancel = %v, %v", ok, err)
+	}
+	bobs, err := f.r.Bids.ListByBuyer(f.ctx, bob)
+	f.must(err)
+	if len(bobs) != 2 {
+		f.t.Fatalf("ListByBuyer(bob) returned %d summaries, want 2", len(bobs))
+	}
+	if s := bobs[1]; s.AuctionID != outbid || s.AuctionStatus != auction.StatusCancelled ||
+		s.CancelReason != "frost" || s.Leading != nil {
+		f.t.Fatalf("cancelled summary = %+v", s)
+	}
+	if s := bobs[0]; s.CancelReason != "" {
+		f.t.Fatalf("live summary = %+v", s)
+	}
+}
+
+func testSettlements(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	buyer := f.user("buyer", user.RoleBuyer)
+	auctionID := f.auction(f.lot(seller), auction.StatusClosed, time.Now().AddDate(0, 0, -2))
+	b1 := f.bid(auctionID, buyer, "3")
+	b2 := f.bid(auctionID, buyer, "2")
+
+	in := settlement.Settlement{
+		AuctionID:          auctionID,
+		Outcome:            settlement.OutcomeSold,
+		Currency:           "USD",
+		ClearingPricePerKG: usd("2"),
+		TotalWeightKG:      1000,
+		TotalAmount:        usd("2000"),
+		CommissionRate:     money.NewRate(50_000),
+		Commission:         usd("100"),
+		FXRate:             &fx.Rate{Base: "USD", Quote: "EUR", Rate: money.NewRate(920_000), UpdatedAt: day("2025-03-01")},
+		Allocations: []settlement.Allocation{
+			{BidID: b1, BuyerID: buyer, BidQuantityKG: 600, QuantityKG: 600, PricePerKG: usd("2"), Amount: usd("1200")},
+			{BidID: b2, BuyerID: buyer, BidQuantityKG: 500, QuantityKG: 400, PricePerKG: usd("2"), Amount: usd("800")},
+		},
+	}
+	id, err := f.r.Settlements.Create(f.ctx, in)
+	f.must(err)
+
+	got, err := f.r.Settlements.GetByAuctionID(f.ctx, auctionID)
+	f.must(err)
+	if got.ID != id || got.Outcome != in.Outcome || got.WinnerID != nil || got.TotalAmount != usd("2000") ||
+		got.CommissionRate != in.CommissionRate || got.Commission != usd("100") || got.CreatedAt.IsZero() || len(got.Allocations) != 2 {
+		f.t.Fatalf("GetByAuctionID = %+v", got)
+	}
+	if r := got.FXRate; r == nil || r.Base != "USD" || r.Quote != "EUR" || r.Rate != in.FXRate.Rate ||
+		!r.UpdatedAt.Equal(in.FXRate.UpdatedAt) {
+		f.t.Fatalf("FXRate = %+v, want %+v", r, in.FXRate)
+	}
+	if a := got.Allocations[0]; a.BidID != b1 || a.PartialFill {
+		f.t.Fatalf("first allocation = %+v", a)
+	}
+	if a := got.Allocations[1]; a.BidID != b2 || !a.PartialFill || a.QuantityKG != 400 {
+		f.t.Fatalf("second allocation = %+v", a)
+	}
+
+	if _, err := f.r.Settlements.Create(f.ctx, in); !errors.Is(err, settlement.ErrAlreadyExists) {
+		f.t.Fatalf("second Create error = %v", err)
+	}
+	if _, err := f.r.Settlements.GetByAuctionID(f.ctx, auctionID+100); !errors.Is(err, settlement.ErrNotFound) {
+		f.t.Fatalf("GetByAuctionID(missing) error = %v", err)
+	}
+}
+
+func testCatalog(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	buyer := f.user("buyer", user.RoleBuyer)
+
+	cheap := f.auction(f.lot(seller), auction.StatusLive, time.Now().Add(-time.Hour))
+	dear := f.auction(f.lot(seller), auction.StatusScheduled, time.Now().Add(time.Hour))
+	f.bid(dear, buyer, "9")
+	// Two and a half decrement intervals in, the clock is two steps down.
+	dutch, err := f.r.Auctions.Create(f.ctx, auction.Auction{
+		LotID:             f.lot(seller),
+		StartDate:         time.Now().Add(-150 * time.Minute).UTC().Truncate(time.Second),
+		DurationDays:      1,
+		Currency:          "USD",
+		InitialPricePerKG: usd("5"),
+		Type:              auction.TypeDutch,
+		Status:            auction.StatusLive,
+		Dutch:             auction.Dutch{FloorPricePerKG: usd("2"), DecrementPerKG: usd("1"), DecrementIntervalMinutes: 60},
+	})
+	f.must(err)
+	f.auction(f.lot(seller), auction.StatusCancelled, time.Now())
+	f.auction(f.lot(seller), auction.StatusLive, time.Now().AddDate(0, 0, -2)) // already over
+
+	asc := catalog.Filter{Sort: catalog.SortPriceAsc}
+	listings, err := f.r.Catalog.Search(f.ctx, asc, nil, 10)
+	f.must(err)
+	if len(listings) != 3 || listings[0].AuctionID != cheap || listings[1].AuctionID != dutch ||
+		listings[2].AuctionID != dear {
+		f.t.Fatalf("Search = %+v, want auctions %d, %d then %d", listings, cheap, dutch, dear)
+	}
+	if l := listings[1]; l.CurrentPricePerKG != usd("3") {
+		f.t.Fatalf("dutch listing = %+v, want the price at 3", l)
+	}
+	if l := listings[2]; l.CurrentPricePerKG != usd("9") || l.Cultivar != "Cavendish" || l.ReserveMet != nil {
+		f.t.Fatalf("listing = %+v", l)
+	}
+
+	after := &catalog.Cursor{Sort: asc.Sort, PricePerKG: listings[0].CurrentPricePerKG, AuctionID: cheap}
+	next, err := f.r.Catalog.Search(f.ctx, asc, after, 10)
+	f.must(err)
+	if len(next) != 2 || next[0].AuctionID != dutch {
+		f.t.Fatalf("Search after cursor = %+v", next)
+	}
+
+	filtered, err := f.r.Catalog.Search(f.ctx, catalog.Filter{Sort: catalog.SortEndingSoonest, MinPricePerKG: usd("5")}, nil, 10)
+	f.must(err)
+	if len(filtered) != 1 || filtered[0].AuctionID != dear {
+		f.t.Fatalf("Search with a price filter = %+v", filtered)
+	}
+	if none, _ := f.r.Catalog.Search(f.ctx, catalog.Filter{Sort: catalog.SortEndingSoonest, Cultivar: "gros michel"}, nil, 10); len(none) != 0 {
+		f.t.Fatalf("Search with a cultivar filter = %+v", none)
+	}
+
+	// Both ends of the harvest range are inclusive.
+	harvested := catalog.Filter{Sort: catalog.SortEndingSoonest, HarvestFrom: day("2025-01-15"), HarvestTo: day("2025-01-15")}
+	if all, _ := f.r.Catalog.Search(f.ctx, harvested, nil, 10); len(all) != 3 || !all[0].HarvestDate.Equal(day("2025-01-15")) {
+		f.t.Fatalf("Search with a harvest range = %+v", all)
+	}
+	later := catalog.Filter{Sort: catalog.SortEndingSoonest, HarvestFrom: day("2025-01-16")}
+	if none, _ := f.r.Catalog.Search(f.ctx, later, nil, 10); len(none) != 0 {
+		f.t.Fatalf("Search with a later harvest = %+v", none)
+	}
+}
+
+func testFXRates(f *fixture) {
+	f.must(f.r.FX.Save(f.ctx, []fx.Rate{
+		{Base: "USD", Quote: "JPY", Rate: money.NewRate(150_500_000)},
+		{Base: "EUR", Quote: "USD", Rate: money.NewRate(1_080_000)},
+	}))
+	rates, err := f.r.FX.List(f.ctx)
+	f.must(err)
+	if len(rates) != 2 || rates[0].Base != "EUR" || rates[1].Quote != "JPY" || rates[1].UpdatedAt.IsZero() {
+		f.t.Fatalf("List = %+v, want EUR/USD then USD/JPY", rates)
+	}
+
+	f.must(f.r.FX.Save(f.ctx, []fx.Rate{{Base: "USD", Quote: "JPY", Rate: money.NewRate(149_250_000)}}))
+	got, err := f.r.FX.Get(f.ctx, "USD", "JPY")
+	f.must(err)
+	if got.Rate != money.NewRate(149_250_000) {
+		f.t.Fatalf("Get after update = %+v", got)
+	}
+	if _, err := f.r.FX.Get(f.ctx, "JPY", "USD"); !errors.Is(err, fx.ErrNotFound) {
+		f.t.Fatalf("Get(inverse) error = %v", err)
+	}
+
+	f.must(f.r.FX.Delete(f.ctx, "EUR", "USD"))
+	if err := f.r.FX.Delete(f.ctx, "EUR", "USD"); !errors.Is(err, fx.ErrNotFound) {
+		f.t.Fatalf("second Delete error = %v", err)
+	}
+	rates, err = f.r.FX.List(f.ctx)
+	f.must(err)
+	if len(rates) != 1 {
+		f.t.Fatalf("List after Delete = %+v", rates)
+	}
+}
+
+func testTransactions(f *fixture) {
+	seller := f.user("seller", user.RoleSeller)
+	errRollback := errors.New("rollback")
+
+	// Writes through several repositories roll back together, including
+	// those made in a nested unit of work.
+	var lotID, auctionID int
+	err := f.r.Tx.Do(f.ctx, transaction.Serializable, func(ctx context.Context) error {
+		var err error
+		if lotID, err = f.r.Lots.Create(ctx, lot.Lot{SellerID: seller, TotalWeightKG: 1000}); err != nil {
+			return err
+		}
+		return f.r.Tx.Do(ctx, transaction.ReadCommitted, func(ctx context.Context) error {
+			if auctionID, err = f.r.Auctions.Create(ctx, auction.Auction{
+				LotID: lotID, StartDate: day("2030-01-01"), DurationDays: 1, Currency: "USD", Type: auction.TypeEnglish,
+				Status: auction.StatusScheduled,
+			}); err != nil {
+				return err
+			}
+			// Reads inside the unit of work see its own writes.
+			if exists, err := f.r.Auctions.ExistsForLot(ctx, lotID); err != nil || !exists {
+				f.t.Errorf("ExistsForLot inside the transaction = %v, %v", exists, err)
+			}
+			return errRollback
+		})
+	})
+	if !errors.Is(err, errRollback) {
+		f.t.Fatalf("Do error = %v", err)
+	}
+	_, err = f.r.Lots.GetByID(f.ctx, lotID)
+	f.wantErr(err, lot.ErrNotFound)
+	_, err = f.r.Auctions.GetByID(f.ctx, auctionID)
+	f.wantErr(err, auction.ErrNotFound)
+
+	err = f.r.Tx.Do(f.ctx, transaction.RepeatableRead, func(ctx context.Context) error {
+		var err error
+		lotID, err = f.r.Lots.Create(ctx, lot.Lot{SellerID: seller, TotalWeightKG: 1000})
+		if err != nil {
+			return err
+		}
+		return f.r.Lots.Update(ctx, lot.Lot{ID: lotID, SellerID: seller, HarvestDate: day("2025-03-01")})
+	})
+	f.must(err)
+	l, err := f.r.Lots.GetByID(f.ctx, lotID)
+	f.must(err)
+	if !l.HarvestDate.Equal(day("2025-03-01")) {
+		f.t.Fatalf("HarvestDate after commit = %s", l.HarvestDate)
+	}
+}
@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/money"
+)
+
+type AuctionRepo struct {
+	store *Store
+}
+
+func NewAuctionRepo(store *Store) *AuctionRepo {
+	return &AuctionRepo{store: store}
+}
+
+func (r *AuctionRepo) Create(ctx context.Context, a auction.Auction) (int, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	if _, ok := r.store.lots[a.LotID]; !ok {
+		return 0, missingRef("lot", a.LotID)
+	}
+	r.store.auctionSeq++
+	a.ID = r.store.auctionSeq
+	// Columns the Postgres insert leaves to their defaults.
+	a.WinningBidID = nil
+	a.ExtensionMinutes = 0
+	a.CancelReason = ""
+	set(tx, r.store.auctions, a.ID, a)
+	return a.ID, nil
+}
+
+func (r *AuctionRepo) GetByID(ctx context.Context, id int) (auction.Auction, error) {
+	defer r.store.read(ctx)()
+	a, ok := r.store.auctions[id]
+	if !ok {
+		return auction.Auction{}, auction.ErrNotFound
+	}
+	return a, nil
+}
+
+func (r *AuctionRepo) Update(ctx context.Context, a auction.Auction) (bool, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	existing, ok := r.store.auctions[a.ID]
+	if !ok || existing.Status != a.Status {
+		return false, nil
+	}
+	existing.StartDate = a.StartDate
+	existing.DurationDays = a.DurationDays
+	existing.InitialPricePerKG = a.InitialPricePerKG
+	existing.ReservePricePerKG = a.ReservePricePerKG
+	existing.BuyNowPricePerKG = a.BuyNowPricePerKG
+	existing.Type = a.Type
+	existing.SoftClose = a.SoftClose
+	existing.Dutch = a.Dutch
+	existing.Clearing = a.Clearing
+	set(tx, r.store.auctions, a.ID, existing)
+	return true, nil
+}
+
+func (r *AuctionRepo) Delete(ctx context.Context, id int) error {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	if r.store.auctionReferenced(id) {
+		return errors.New("auction is still referenced by bids or a settlement")
+	}
+	remove(tx, r.store.auctions, id)
+	return nil
+}
+
+func (r *AuctionRepo) List(ctx context.Context) ([]auction.Auction, error) {
+	defer r.store.read(ctx)()
+	return r.store.listAuctions(func(auction.Auction) bool { return true }), nil
+}
+
+func (r *AuctionRepo) ListByStatus(ctx context.Context, statuses ...auction.Status) ([]auction.Auction, error) {
+	defer r.store.read(ctx)()
+	return r.store.listAuctions(func(a auction.Auction) bool {
+		return slices.Contains(statuses, a.Status)
+	}), nil
+}
+
+func (r *AuctionRepo) ListBySeller(ctx context.Context, sellerID int) ([]auction.Summary, error) {
+	defer r.store.read(ctx)()
+	now := time.Now()
+	var summaries []auction.Summary
+	for _, a := range r.store.listAuctions(func(a auction.Auction) bool {
+		return r.store.lots[a.LotID].SellerID == sellerID
+	}) {
+		l := r.store.lots[a.LotID]
+		highest, count := r.store.bidStats(a.ID)
+		s := auction.Summary{
+			Auction:         a,
+			Cultivar:        l.Cultivar,
+			TotalWeightKG:   l.TotalWeightKG,
+			HighestBidPerKG: visibleHighest(a, highest),
+			BidCount:        count,
+		}
+		s.EndsAt = a.EndTime()
+		s.TimeRemainingSeconds = timeRemaining(a, s.EndsAt, now)
+		summaries = append(summaries, s)
+	}
+	slices.Reverse(summaries)
+	return summaries, nil
+}
+
+func (r *AuctionRepo) ExistsForLot(ctx context.Context, lotID int) (bool, error) {
+	defer r.store.read(ctx)()
+	for _, a := range r.store.auctions {
+		if a.LotID == lotID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *AuctionRepo) Transition(ctx context.Context, id int, from, to auction.Status) (bool, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	a, ok := r.store.auctions[id]
+	if !ok || a.Status != from {
+		return false, nil
+	}
+	a.Status = to
+	set(tx, r.store.auctions, id, a)
+	return true, nil
+}
+
+func (r *AuctionRepo) Cancel(ctx context.Context, id int, reason string) (bool, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	a, ok := r.store.auctions[id]
+	if !ok || (a.Status != auction.StatusScheduled && a.Status != auction.StatusLive) {
+		return false, nil
+	}
+	a.Status = auction.StatusCancelled
+	a.CancelReason = reason
+	set(tx, r.store.auctions, id, a)
+	return true, nil
+}
+
+func (r *AuctionRepo) Close(ctx context.Context, id int) (bool, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	a, ok := r.store.auctions[id]
+	if !ok {
+		return false, auction.ErrNotFound
+	}
+	if a.Status != auction.StatusLive {
+		return false, nil
+	}
+	// A soft-close extension may have moved the end since the caller looked.
+	if time.Now().Before(a.EndTime()) {
+		return false, nil
+	}
+
+	a.Status = auction.StatusClosed
+	a.WinningBidID = nil
+	if highest := r.store.highestBid(id, 0); highest != nil {
+		a.WinningBidID = &highest.ID
+	}
+	set(tx, r.store.auctions, id, a)
+	return true, nil
+}
+
+// listAuctions returns the auctions keep accepts in ID order.
+func (s *Store) listAuctions(keep func(auction.Auction) bool) []auction.Auction {
+	var auctions []auction.Auction
+	for _, a := range s.auctions {
+		if keep(a) {
+			auctions = append(auctions, a)
+		}
+	}
+	sort.Slice(auctions, func(i, j int) bool { return auctions[i].ID < auctions[j].ID })
+	return auctions
+}
+
+func (s *Store) auctionReferenced(id int) bool {
+	for _, b := range s.bids {
+		if b.AuctionID == id {
+			return true
+		}
+	}
+	for _, p := range s.proxies {
+		if p.AuctionID == id {
+			return true
+		}
+	}
+	for _, st := range s.settlements {
+		if st.AuctionID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// bidStats returns the auction's highest bid price, nil without bids, and
+// how many bids it has.
+func (s *Store) bidStats(auctionID int) (*money.Amount, int) {
+	var highest *money.Amount
+	count := 0
+	for _, b := range s.bids {
+		if b.AuctionID != auctionID {
+			continue
+		}
+		count++
+		if highest == nil || b.BidPricePerKG.Cmp(*highest) > 0 {
+			price := b.BidPricePerKG
+			highest = &price
+		}
+	}
+	return highest, count
+}
+
+// visibleHighest hides the highest bid of a sealed auction that is still
+// taking bids.
+func visibleHighest(a auction.Auction, highest *money.Amount) *money.Amount {
+	if a.Type.Sealed() && (a.Status == auction.StatusScheduled || a.Status == auction.StatusLive) {
+		return nil
+	}
+	return highest
+}
+
+// timeRemaining is the whole seconds left until end while the auction is
+// scheduled or live, and zero otherwise.
+func timeRemaining(a auction.Auction, end, now time.Time) int64 {
+	if a.Status != auction.StatusScheduled && a.Status != auction.StatusLive {
+		return 0
+	}
+	return int64(math.Round(math.Max(end.Sub(now).Seconds(), 0)))
+}
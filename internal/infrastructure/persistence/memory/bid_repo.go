@@ -0,0 +1,301 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/lot"
+)
+
+type BidRepo struct {
+	store *Store
+}
+
+func NewBidRepo(store *Store) *BidRepo {
+	return &BidRepo{store: store}
+}
+
+func (r *BidRepo) Create(ctx context.Context, b bid.Bid) (int, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	return r.store.insertBid(tx, b)
+}
+
+func (s *Store) insertBid(tx *unitOfWork, b bid.Bid) (int, error) {
+	if _, ok := s.auctions[b.AuctionID]; !ok {
+		return 0, missingRef("auction", b.AuctionID)
+	}
+	if _, ok := s.users[b.BuyerID]; !ok {
+		return 0, missingRef("user", b.BuyerID)
+	}
+	s.bidSeq++
+	b.ID = s.bidSeq
+	set(tx, s.bids, b.ID, b)
+	return b.ID, nil
+}
+
+func (r *BidRepo) WithAuctionLock(ctx context.Context, auctionID int, fn func(l bid.Locked) error) error {
+	return r.store.atomic(ctx, func(ctx context.Context, u *unitOfWork) error {
+		a, ok := r.store.auctions[auctionID]
+		if !ok {
+			return bid.ErrAuctionNotFound
+		}
+		return fn(&lockedAuction{store: r.store, tx: u, auction: a})
+	})
+}
+
+func (r *BidRepo) GetByID(ctx context.Context, id int) (bid.Bid, error) {
+	defer r.store.read(ctx)()
+	b, ok := r.store.bids[id]
+	if !ok {
+		return bid.Bid{}, bid.ErrNotFound
+	}
+	return b, nil
+}
+
+func (r *BidRepo) Highest(ctx context.Context, auctionID int) (*bid.Bid, error) {
+	defer r.store.read(ctx)()
+	return r.store.highestBid(auctionID, 0), nil
+}
+
+// highestBid returns the auction's highest bid, the earliest one on equal
+// prices, limited to buyerID's bids unless it is zero.
+func (s *Store) highestBid(auctionID, buyerID int) *bid.Bid {
+	var best *bid.Bid
+	for _, b := range s.bids {
+		if b.AuctionID != auctionID || (buyerID != 0 && b.BuyerID != buyerID) {
+			continue
+		}
+		if best == nil {
+			best = &b
+			continue
+		}
+		if c := b.BidPricePerKG.Cmp(best.BidPricePerKG); c > 0 || (c == 0 && b.ID < best.ID) {
+			best = &b
+		}
+	}
+	return best
+}
+
+func (r *BidRepo) GetProxy(ctx context.Context, auctionID, buyerID int) (bid.ProxyBid, error) {
+	defer r.store.read(ctx)()
+	for _, p := range r.store.proxies {
+		if p.AuctionID == auctionID && p.BuyerID == buyerID {
+			return p, nil
+		}
+	}
+	return bid.ProxyBid{}, bid.ErrProxyNotFound
+}
+
+func (r *BidRepo) Update(ctx context.Context, b bid.Bid) error {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	existing, ok := r.store.bids[b.ID]
+	if !ok {
+		return nil
+	}
+	existing.BidPricePerKG = b.BidPricePerKG
+	set(tx, r.store.bids, b.ID, existing)
+	return nil
+}
+
+func (r *BidRepo) Delete(ctx context.Context, id int) error {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	return r.store.deleteBid(tx, id)
+}
+
+func (s *Store) deleteBid(tx *unitOfWork, id int) error {
+	for _, a := range s.auctions {
+		if a.WinningBidID != nil && *a.WinningBidID == id {
+			return errors.New("bid is still referenced by its auction")
+		}
+	}
+	for _, st := range s.settlements {
+		if st.WinningBidID != nil && *st.WinningBidID == id {
+			return errors.New("bid is still referenced by a settlement")
+		}
+		for _, al := range st.Allocations {
+			if al.BidID == id {
+				return errors.New("bid is still referenced by a settlement")
+			}
+		}
+	}
+	remove(tx, s.bids, id)
+	return nil
+}
+
+func (r *BidRepo) ListByAuctionID(ctx context.Context, auctionID int) ([]bid.Bid, error) {
+	defer r.store.read(ctx)()
+	var bids []bid.Bid
+	for _, b := range r.store.bids {
+		if b.AuctionID == auctionID {
+			bids = append(bids, b)
+		}
+	}
+	sort.Slice(bids, func(i, j int) bool { return bids[i].ID < bids[j].ID })
+	return bids, nil
+}
+
+func (r *BidRepo) ListByBuyer(ctx context.Context, buyerID int) ([]bid.Summary, error) {
+	defer r.store.read(ctx)()
+	counts := map[int]int{}
+	for _, b := range r.store.bids {
+		if b.BuyerID == buyerID {
+			counts[b.AuctionID]++
+		}
+	}
+
+	var summaries []bid.Summary
+	for auctionID, count := range counts {
+		a := r.store.auctions[auctionID]
+		s := bid.Summary{
+			AuctionID:     a.ID,
+			AuctionType:   a.Type,
+			AuctionStatus: a.Status,
+			CancelReason:  a.CancelReason,
+			BestBid:       *r.store.highestBid(a.ID, buyerID),
+			BidCount:      count,
+		}
+		s.EndsAt = a.EndTime()
+		open := a.Status == auction.StatusScheduled || a.Status == auction.StatusLive
+		if open && a.Type != auction.TypeMultiUnit && !a.Type.Sealed() {
+			leading := r.store.highestBid(a.ID, 0).BuyerID == buyerID
+			s.Leading = &leading
+		}
+		if a.Status == auction.StatusSettled {
+			s.Won = r.store.wonBy(a.ID, buyerID)
+		}
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if !summaries[i].EndsAt.Equal(summaries[j].EndsAt) {
+			return summaries[i].EndsAt.After(summaries[j].EndsAt)
+		}
+		return summaries[i].AuctionID > summaries[j].AuctionID
+	})
+	return summaries, nil
+}
+
+// wonBy reports whether the auction's settlement names buyerID as the winner
+// or gives them an allocation.
+func (s *Store) wonBy(auctionID, buyerID int) bool {
+	for _, st := range s.settlements {
+		if st.AuctionID != auctionID {
+			continue
+		}
+		if st.WinnerID != nil && *st.WinnerID == buyerID {
+			return true
+		}
+		for _, al := range st.Allocations {
+			if al.BuyerID == buyerID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lockedAuction implements bid.Locked inside WithAuctionLock's unit of work.
+type lockedAuction struct {
+	store   *Store
+	tx      *unitOfWork
+	auction auction.Auction
+}
+
+func (l *lockedAuction) Auction() auction.Auction {
+	return l.auction
+}
+
+func (l *lockedAuction) Highest() (*bid.Bid, error) {
+	return l.store.highestBid(l.auction.ID, 0), nil
+}
+
+func (l *lockedAuction) Proxies() ([]bid.ProxyBid, error) {
+	var proxies []bid.ProxyBid
+	for _, p := range l.store.proxies {
+		if p.AuctionID == l.auction.ID {
+			proxies = append(proxies, p)
+		}
+	}
+	sort.Slice(proxies, func(i, j int) bool {
+		if !proxies[i].RegisteredAt.Equal(proxies[j].RegisteredAt) {
+			return proxies[i].RegisteredAt.Before(proxies[j].RegisteredAt)
+		}
+		return proxies[i].ID < proxies[j].ID
+	})
+	return proxies, nil
+}
+
+func (l *lockedAuction) CreateBid(b bid.Bid) (int, error) {
+	return l.store.insertBid(l.tx, b)
+}
+
+func (l *lockedAuction) BuyerBid(buyerID int) (*bid.Bid, error) {
+	return l.store.highestBid(l.auction.ID, buyerID), nil
+}
+
+func (l *lockedAuction) DeleteBid(id int) error {
+	if b, ok := l.store.bids[id]; !ok || b.AuctionID != l.auction.ID {
+		return nil
+	}
+	return l.store.deleteBid(l.tx, id)
+}
+
+func (l *lockedAuction) SaveProxy(p bid.ProxyBid) (int, error) {
+	if _, ok := l.store.users[p.BuyerID]; !ok {
+		return 0, missingRef("user", p.BuyerID)
+	}
+	p.AuctionID = l.auction.ID
+	p.RegisteredAt = time.Now()
+	p.ID = 0
+	for id, existing := range l.store.proxies {
+		if existing.AuctionID == p.AuctionID && existing.BuyerID == p.BuyerID {
+			p.ID = id
+			if existing.MaxPricePerKG == p.MaxPricePerKG {
+				p.RegisteredAt = existing.RegisteredAt
+			}
+		}
+	}
+	if p.ID == 0 {
+		l.store.proxySeq++
+		p.ID = l.store.proxySeq
+	}
+	set(l.tx, l.store.proxies, p.ID, p)
+	return p.ID, nil
+}
+
+func (l *lockedAuction) Extend(minutes int) (auction.Auction, error) {
+	a := l.store.auctions[l.auction.ID]
+	a.ExtensionMinutes += minutes
+	set(l.tx, l.store.auctions, a.ID, a)
+	l.auction.ExtensionMinutes += minutes
+	return l.auction, nil
+}
+
+func (l *lockedAuction) LotWeightKG() (int, error) {
+	lt, ok := l.store.lots[l.auction.LotID]
+	if !ok {
+		return 0, lot.ErrNotFound
+	}
+	return lt.TotalWeightKG, nil
+}
+
+// Close moves the auction from live to closed, provided it still has the
+// status it was locked with.
+func (l *lockedAuction) Close(winningBidID int) (auction.Auction, error) {
+	a := l.store.auctions[l.auction.ID]
+	if a.Status != l.auction.Status || !a.Status.CanTransitionTo(auction.StatusClosed) {
+		return auction.Auction{}, auction.ErrInvalidTransition
+	}
+	a.Status = auction.StatusClosed
+	a.WinningBidID = &winningBidID
+	set(l.tx, l.store.auctions, a.ID, a)
+	l.auction.Status = auction.StatusClosed
+	l.auction.WinningBidID = &winningBidID
+	return l.auction, nil
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/catalog"
+)
+
+type CatalogRepo struct {
+	store *Store
+}
+
+func NewCatalogRepo(store *Store) *CatalogRepo {
+	return &CatalogRepo{store: store}
+}
+
+func (r *CatalogRepo) Search(ctx context.Context, f catalog.Filter, after *catalog.Cursor, limit int) ([]catalog.Listing, error) {
+	defer r.store.read(ctx)()
+	now := time.Now()
+
+	var listings []catalog.Listing
+	for _, a := range r.store.listAuctions(func(a auction.Auction) bool {
+		return a.Status == auction.StatusScheduled || a.Status == auction.StatusLive
+	}) {
+		l := r.store.listing(a, now)
+		if !l.EndsAt.After(now) || !matches(l, f) {
+			continue
+		}
+		listings = append(listings, l)
+	}
+
+	less := func(x, y catalog.Listing) bool {
+		switch f.Sort {
+		case catalog.SortPriceAsc, catalog.SortPriceDesc:
+			if x.Currency != y.Currency {
+				return x.Currency < y.Currency
+			}
+			if c := x.CurrentPricePerKG.Cmp(y.CurrentPricePerKG); c != 0 {
+				return (c < 0) == (f.Sort == catalog.SortPriceAsc)
+			}
+		default:
+			if !x.EndsAt.Equal(y.EndsAt) {
+				return x.EndsAt.Before(y.EndsAt)
+			}
+		}
+		return x.AuctionID < y.AuctionID
+	}
+	sort.Slice(listings, func(i, j int) bool { return less(listings[i], listings[j]) })
+
+	if after != nil {
+		pos := catalog.Listing{
+			AuctionID:         after.AuctionID,
+			EndsAt:            after.EndsAt,
+			Currency:          after.PricePerKG.Currency(),
+			CurrentPricePerKG: after.PricePerKG,
+		}
+		i := sort.Search(len(listings), func(i int) bool { return less(pos, listings[i]) })
+		listings = listings[i:]
+	}
+	if len(listings) > limit {
+		listings = listings[:limit]
+	}
+	return listings, nil
+}
+
+// listing builds the catalogue entry for a, as the Postgres catalogue query
+// does.
+func (s *Store) listing(a auction.Auction, now time.Time) catalog.Listing {
+	l := s.lots[a.LotID]
+	highest, _ := s.bidStats(a.ID)
+
+	listing := catalog.Listing{
+		AuctionID:         a.ID,
+		LotID:             a.LotID,
+		Type:              a.Type,
+		Status:            a.Status,
+		StartsAt:          a.StartDate,
+		EndsAt:            a.EndTime(),
+		Currency:          a.Currency,
+		InitialPricePerKG: a.InitialPricePerKG,
+		CurrentPricePerKG: a.InitialPricePerKG,
+		BuyNowPricePerKG:  a.BuyNowPricePerKG,
+		Cultivar:          l.Cultivar,
+		PlantedCountry:    l.PlantedCountry,
+		HarvestDate:       l.HarvestDate,
+		TotalWeightKG:     l.TotalWeightKG,
+	}
+	switch {
+	case a.Type == auction.TypeDutch:
+		listing.CurrentPricePerKG = a.PriceAt(now)
+	case a.Type.Sealed():
+	case highest != nil:
+		listing.CurrentPricePerKG = *highest
+	}
+	if !a.ReservePricePerKG.IsZero() && !a.Type.Sealed() {
+		met := highest != nil && a.ReserveMet(*highest)
+		listing.ReserveMet = &met
+	}
+	return listing
+}
+
+func matches(l catalog.Listing, f catalog.Filter) bool {
+	switch {
+	case f.Cultivar != "" && !strings.EqualFold(l.Cultivar, f.Cultivar):
+		return false
+	case f.PlantedCountry != "" && !strings.EqualFold(l.PlantedCountry, f.PlantedCountry):
+		return false
+	case !f.HarvestFrom.IsZero() && l.HarvestDate.Before(f.HarvestFrom):
+		return false
+	case !f.HarvestTo.IsZero() && l.HarvestDate.After(f.HarvestTo):
+		return false
+	case f.MinWeightKG > 0 && l.TotalWeightKG < f.MinWeightKG:
+		return false
+	case f.MaxWeightKG > 0 && l.TotalWeightKG > f.MaxWeightKG:
+		return false
+	case !f.MinPricePerKG.IsZero() && (l.Currency != f.MinPricePerKG.Currency() || l.CurrentPricePerKG.Cmp(f.MinPricePerKG) < 0):
+		return false
+	case !f.MaxPricePerKG.IsZero() && (l.Currency != f.MaxPricePerKG.Currency() || l.CurrentPricePerKG.Cmp(f.MaxPricePerKG) > 0):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/money"
+)
+
+type FXRepo struct {
+	store *Store
+}
+
+func NewFXRepo(store *Store) *FXRepo {
+	return &FXRepo{store: store}
+}
+
+func (r *FXRepo) List(ctx context.Context) ([]fx.Rate, error) {
+	defer r.store.read(ctx)()
+	rates := make([]fx.Rate, 0, len(r.store.fxRates))
+	for _, rate := range r.store.fxRates {
+		rates = append(rates, rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Base != rates[j].Base {
+			return rates[i].Base < rates[j].Base
+		}
+		return rates[i].Quote < rates[j].Quote
+	})
+	return rates, nil
+}
+
+func (r *FXRepo) Get(ctx context.Context, base, quote money.Currency) (fx.Rate, error) {
+	defer r.store.read(ctx)()
+	rate, ok := r.store.fxRates[fxPair{base, quote}]
+	if !ok {
+		return fx.Rate{}, fx.ErrNotFound
+	}
+	return rate, nil
+}
+
+func (r *FXRepo) Save(ctx context.Context, rates []fx.Rate) error {
+	return r.store.atomic(ctx, func(ctx context.Context, u *unitOfWork) error {
+		now := time.Now()
+		for _, rate := range rates {
+			rate.UpdatedAt = now
+			set(u, r.store.fxRates, fxPair{rate.Base, rate.Quote}, rate)
+		}
+		return nil
+	})
+}
+
+func (r *FXRepo) Delete(ctx context.Context, base, quote money.Currency) error {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	if _, ok := r.store.fxRates[fxPair{base, quote}]; !ok {
+		return fx.ErrNotFound
+	}
+	remove(tx, r.store.fxRates, fxPair{base, quote})
+	return nil
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/lot"
+)
+
+type LotRepo struct {
+	store *Store
+}
+
+func NewLotRepo(store *Store) *LotRepo {
+	return &LotRepo{store: store}
+}
+
+func (r *LotRepo) Create(ctx context.Context, l lot.Lot) (int, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	if _, ok := r.store.users[l.SellerID]; !ok {
+		return 0, missingRef("user", l.SellerID)
+	}
+	r.store.lotSeq++
+	l.ID = r.store.lotSeq
+	set(tx, r.store.lots, l.ID, l)
+	return l.ID, nil
+}
+
+func (r *LotRepo) GetByID(ctx context.Context, id int) (lot.Lot, error) {
+	defer r.store.read(ctx)()
+	l, ok := r.store.lots[id]
+	if !ok {
+		return lot.Lot{}, lot.ErrNotFound
+	}
+	return l, nil
+}
+
+func (r *LotRepo) Update(ctx context.Context, l lot.Lot) error {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	existing, ok := r.store.lots[l.ID]
+	if !ok || existing.SellerID != l.SellerID {
+		return nil
+	}
+	existing.HarvestDate = l.HarvestDate
+	set(tx, r.store.lots, l.ID, existing)
+	return nil
+}
+
+// Delete removes a lot whose auctions were all cancelled, together with
+// those auctions and their bids; see lot.Repository.
+func (r *LotRepo) Delete(ctx context.Context, id int) error {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	for _, st := range r.store.settlements {
+		if a, ok := r.store.auctions[st.AuctionID]; ok && a.LotID == id {
+			return lot.ErrSettled
+		}
+	}
+	var scheduled bool
+	for _, a := range r.store.auctions {
+		if a.LotID != id {
+			continue
+		}
+		switch a.Status {
+		case auction.StatusCancelled:
+		case auction.StatusScheduled:
+			scheduled = true
+		default:
+			return lot.ErrAuctionStarted
+		}
+	}
+	if scheduled {
+		return lot.ErrHasAuction
+	}
+
+	for auctionID, a := range r.store.auctions {
+		if a.LotID != id {
+			continue
+		}
+		for proxyID, p := range r.store.proxies {
+			if p.AuctionID == auctionID {
+				remove(tx, r.store.proxies, proxyID)
+			}
+		}
+		for bidID, b := range r.store.bids {
+			if b.AuctionID == auctionID {
+				remove(tx, r.store.bids, bidID)
+			}
+		}
+		remove(tx, r.store.auctions, auctionID)
+	}
+	remove(tx, r.store.lots, id)
+	return nil
+}
+
+func (r *LotRepo) List(ctx context.Context) ([]lot.Lot, error) {
+	defer r.store.read(ctx)()
+	var lots []lot.Lot
+	for _, l := range r.store.lots {
+		lots = append(lots, l)
+	}
+	sort.Slice(lots, func(i, j int) bool { return lots[i].ID < lots[j].ID })
+	return lots, nil
+}
+
+func (r *LotRepo) ListBySeller(ctx context.Context, sellerID int) ([]lot.Summary, error) {
+	defer r.store.read(ctx)()
+	now := time.Now()
+	var summaries []lot.Summary
+	for _, l := range r.store.lots {
+		if l.SellerID != sellerID {
+			continue
+		}
+		s := lot.Summary{Lot: l}
+		for _, a := range r.store.auctions {
+			if a.LotID != l.ID {
+				continue
+			}
+			id := a.ID
+			s.AuctionID = &id
+			s.AuctionStatus = string(a.Status)
+			highest, count := r.store.bidStats(a.ID)
+			s.HighestBidPerKG = visibleHighest(a, highest)
+			s.BidCount = count
+			end := a.EndTime()
+			s.EndsAt = &end
+			s.TimeRemainingSeconds = timeRemaining(a, end, now)
+		}
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].ID > summaries[j].ID })
+	return summaries, nil
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"banana-auction/internal/domain/settlement"
+)
+
+type SettlementRepo struct {
+	store *Store
+}
+
+func NewSettlementRepo(store *Store) *SettlementRepo {
+	return &SettlementRepo{store: store}
+}
+
+func (r *SettlementRepo) Create(ctx context.Context, s settlement.Settlement) (int, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	if _, ok := r.store.auctions[s.AuctionID]; !ok {
+		return 0, missingRef("auction", s.AuctionID)
+	}
+	for _, existing := range r.store.settlements {
+		if existing.AuctionID == s.AuctionID {
+			return 0, settlement.ErrAlreadyExists
+		}
+	}
+	r.store.settlementSeq++
+	s.ID = r.store.settlementSeq
+	s.CreatedAt = time.Now()
+	s.Allocations = slices.Clone(s.Allocations)
+	if s.FXRate != nil {
+		rate := *s.FXRate
+		s.FXRate = &rate
+	}
+	for i := range s.Allocations {
+		a := &s.Allocations[i]
+		a.PartialFill = a.QuantityKG < a.BidQuantityKG
+	}
+	set(tx, r.store.settlements, s.ID, s)
+	return s.ID, nil
+}
+
+func (r *SettlementRepo) GetByAuctionID(ctx context.Context, auctionID int) (settlement.Settlement, error) {
+	defer r.store.read(ctx)()
+	for _, s := range r.store.settlements {
+		if s.AuctionID == auctionID {
+			s.Allocations = slices.Clone(s.Allocations)
+			return s, nil
+		}
+	}
+	return settlement.Settlement{}, settlement.ErrNotFound
+}
@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/settlement"
+	"banana-auction/internal/domain/transaction"
+	"banana-auction/internal/domain/user"
+)
+
+// Store holds every table of the in-memory backend behind one lock, so
+// operations that touch several tables (the lot cascade, closing an
+// auction, units of work) are atomic like their Postgres counterparts.
+// IDs come from per-table sequences that, like SERIAL columns, are not
+// rolled back.
+type Store struct {
+	mu sync.RWMutex
+
+	users       map[int]user.User
+	tokens      map[string]user.RefreshToken
+	lots        map[int]lot.Lot
+	auctions    map[int]auction.Auction
+	bids        map[int]bid.Bid
+	proxies     map[int]bid.ProxyBid
+	settlements map[int]settlement.Settlement
+	fxRates     map[fxPair]fx.Rate
+
+	userSeq, lotSeq, auctionSeq, bidSeq, proxySeq, settlementSeq int
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:       map[int]user.User{},
+		tokens:      map[string]user.RefreshToken{},
+		lots:        map[int]lot.Lot{},
+		auctions:    map[int]auction.Auction{},
+		bids:        map[int]bid.Bid{},
+		proxies:     map[int]bid.ProxyBid{},
+		settlements: map[int]settlement.Settlement{},
+		fxRates:     map[fxPair]fx.Rate{},
+	}
+}
+
+// fxPair is the key of the rate table.
+type fxPair struct {
+	base, quote money.Currency
+}
+
+type txKey struct{}
+
+// unitOfWork is an open TxManager.Do or WithAuctionLock. It holds the
+// store's write lock and records how to undo each write made in it.
+type unitOfWork struct {
+	store *Store
+	undo  []func()
+}
+
+func (u *unitOfWork) rollback() {
+	for i := len(u.undo) - 1; i >= 0; i-- {
+		u.undo[i]()
+	}
+}
+
+// current returns the unit of work ctx carries on s, if any.
+func (s *Store) current(ctx context.Context) *unitOfWork {
+	if u, ok := ctx.Value(txKey{}).(*unitOfWork); ok && u.store == s {
+		return u
+	}
+	return nil
+}
+
+// read locks s for reading and returns the unlock function. Inside a unit
+// of work the write lock is already held.
+func (s *Store) read(ctx context.Context) func() {
+	if s.current(ctx) != nil {
+		return func() {}
+	}
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+// write locks s for writing and returns the unit of work to record undo
+// steps in, which is nil outside of one, and the unlock function.
+func (s *Store) write(ctx context.Context) (*unitOfWork, func()) {
+	if u := s.current(ctx); u != nil {
+		return u, func() {}
+	}
+	s.mu.Lock()
+	return nil, s.mu.Unlock
+}
+
+// atomic runs fn in the caller's unit of work, or in a new one whose writes
+// are undone when fn fails.
+func (s *Store) atomic(ctx context.Context, fn func(ctx context.Context, u *unitOfWork) error) error {
+	if u := s.current(ctx); u != nil {
+		return fn(ctx, u)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := &unitOfWork{store: s}
+	if err := fn(context.WithValue(ctx, txKey{}, u), u); err != nil {
+		u.rollback()
+		return err
+	}
+	return nil
+}
+
+// set stores v under k, recording the undo step in u when there is one.
+func set[K comparable, V any](u *unitOfWork, m map[K]V, k K, v V) {
+	if u != nil {
+		old, had := m[k]
+		u.undo = append(u.undo, func() {
+			if had {
+				m[k] = old
+			} else {
+				delete(m, k)
+			}
+		})
+	}
+	m[k] = v
+}
+
+// remove deletes k, recording the undo step in u when there is one.
+func remove[K comparable, V any](u *unitOfWork, m map[K]V, k K) {
+	old, had := m[k]
+	if !had {
+		return
+	}
+	if u != nil {
+		u.undo = append(u.undo, func() { m[k] = old })
+	}
+	delete(m, k)
+}
+
+// TxManager implements transaction.Manager for a Store. A unit of work holds
+// the store's write lock until it finishes, so every isolation level is
+// effectively serializable and nothing ever needs retrying.
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store: store}
+}
+
+func (m *TxManager) Do(ctx context.Context, iso transaction.Isolation, fn func(ctx context.Context) error) error {
+	return m.store.atomic(ctx, func(ctx context.Context, _ *unitOfWork) error {
+		return fn(ctx)
+	})
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"banana-auction/internal/domain/user"
+)
+
+type TokenRepo struct {
+	store *Store
+}
+
+func NewTokenRepo(store *Store) *TokenRepo {
+	return &TokenRepo{store: store}
+}
+
+func (r *TokenRepo) CreateRefreshToken(ctx context.Context, t user.RefreshToken) error {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	if _, ok := r.store.tokens[t.ID]; ok {
+		return fmt.Errorf("refresh token %s already exists", t.ID)
+	}
+	if _, ok := r.store.users[t.UserID]; !ok {
+		return missingRef("user", t.UserID)
+	}
+	t.UsedAt, t.RevokedAt = nil, nil
+	set(tx, r.store.tokens, t.ID, t)
+	return nil
+}
+
+func (r *TokenRepo) GetRefreshToken(ctx context.Context, id string) (user.RefreshToken, error) {
+	defer r.store.read(ctx)()
+	t, ok := r.store.tokens[id]
+	if !ok {
+		return user.RefreshToken{}, user.ErrInvalidRefreshToken
+	}
+	return t, nil
+}
+
+func (r *TokenRepo) MarkRefreshTokenUsed(ctx context.Context, id string) (bool, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	t, ok := r.store.tokens[id]
+	if !ok || t.UsedAt != nil || t.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	set(tx, r.store.tokens, id, t)
+	return true, nil
+}
+
+func (r *TokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	now := time.Now()
+	for id, t := range r.store.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+			set(tx, r.store.tokens, id, t)
+		}
+	}
+	return nil
+}
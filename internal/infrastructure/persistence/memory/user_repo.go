@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"banana-auction/internal/domain/user"
+)
+
+// missingRef is what the store returns where Postgres would report a foreign
+// key violation.
+func missingRef(table string, id int) error {
+	return fmt.Errorf("%s %d does not exist", table, id)
+}
+
+type UserRepo struct {
+	store *Store
+}
+
+func NewUserRepo(store *Store) *UserRepo {
+	return &UserRepo{store: store}
+}
+
+func (r *UserRepo) Create(ctx context.Context, u user.User) (int, error) {
+	tx, unlock := r.store.write(ctx)
+	defer unlock()
+	for _, existing := range r.store.users {
+		if existing.Username == u.Username {
+			return 0, user.ErrUsernameTaken
+		}
+	}
+	r.store.userSeq++
+	u.ID = r.store.userSeq
+	set(tx, r.store.users, u.ID, u)
+	return u.ID, nil
+}
+
+func (r *UserRepo) GetByUsername(ctx context.Context, username string) (user.User, error) {
+	defer r.store.read(ctx)()
+	for _, u := range r.store.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return user.User{}, user.ErrNotFound
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, id int) (user.User, error) {
+	defer r.store.read(ctx)()
+	u, ok := r.store.users[id]
+	if !ok {
+		return user.User{}, user.ErrNotFound
+	}
+	return u, nil
+}
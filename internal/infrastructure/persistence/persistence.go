@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"fmt"
+
+	"banana-auction/config"
+	"banana-auction/internal/domain/auction"
+	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/catalog"
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/settlement"
+	"banana-auction/internal/domain/transaction"
+	"banana-auction/internal/domain/user"
+	"banana-auction/internal/infrastructure/persistence/memory"
+	"banana-auction/internal/infrastructure/persistence/postgres"
+	"banana-auction/internal/infrastructure/persistence/sqlite"
+)
+
+// Repositories is one storage backend's implementation of every domain
+// repository plus the transaction manager that spans them.
+type Repositories struct {
+	Users       user.Repository
+	Tokens      user.TokenRepository
+	Lots        lot.Repository
+	Auctions    auction.Repository
+	Bids        bid.Repository
+	Settlements settlement.Repository
+	Catalog     catalog.Repository
+	FX          fx.Repository
+	Tx          transaction.Manager
+}
+
+// Open returns the repositories of the backend cfg.DbDriver names. The
+// postgres and sqlite backends connect and apply pending migrations first.
+func Open(cfg *config.Config) (*Repositories, error) {
+	switch cfg.DbDriver {
+	case config.DriverPostgres:
+		if err := postgres.InitDB(cfg); err != nil {
+			return nil, err
+		}
+		return Postgres(), nil
+	case config.DriverSQLite:
+		if err := sqlite.InitDB(cfg); err != nil {
+			return nil, err
+		}
+		return SQLite(), nil
+	case config.DriverMemory:
+		return Memory(memory.NewStore()), nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.DbDriver)
+	}
+}
+
+// Postgres returns repositories on the connection opened by postgres.Connect.
+func Postgres() *Repositories {
+	db := postgres.GetDB()
+	return &Repositories{
+		Users:       postgres.NewUserRepo(db),
+		Tokens:      postgres.NewTokenRepo(db),
+		Lots:        postgres.NewLotRepo(db),
+		Auctions:    postgres.NewAuctionRepo(db),
+		Bids:        postgres.NewBidRepo(db),
+		Settlements: postgres.NewSettlementRepo(db),
+		Catalog:     postgres.NewCatalogRepo(db),
+		FX:          postgres.NewFXRepo(db),
+		Tx:          postgres.NewTxManager(db),
+	}
+}
+
+// SQLite returns repositories on the database opened by sqlite.Connect.
+func SQLite() *Repositories {
+	db := sqlite.GetDB()
+	return &Repositories{
+		Users:       sqlite.NewUserRepo(db),
+		Tokens:      sqlite.NewTokenRepo(db),
+		Lots:        sqlite.NewLotRepo(db),
+		Auctions:    sqlite.NewAuctionRepo(db),
+		Bids:        sqlite.NewBidRepo(db),
+		Settlements: sqlite.NewSettlementRepo(db),
+		Catalog:     sqlite.NewCatalogRepo(db),
+		FX:          sqlite.NewFXRepo(db),
+		Tx:          sqlite.NewTxManager(db),
+	}
+}
+
+// Memory returns repositories that keep everything in store. Data is lost
+// when the process exits.
+func Memory(store *memory.Store) *Repositories {
+	return &Repositories{
+		Users:       memory.NewUserRepo(store),
+		Tokens:      memory.NewTokenRepo(store),
+		Lots:        memory.NewLotRepo(store),
+		Auctions:    memory.NewAuctionRepo(store),
+		Bids:        memory.NewBidRepo(store),
+		Settlements: memory.NewSettlementRepo(store),
+		Catalog:     memory.NewCatalogRepo(store),
+		FX:          memory.NewFXRepo(store),
+		Tx:          memory.NewTxManager(store),
+	}
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"fmt"
+
+	"banana-auction/internal/domain/money"
+)
+
+// Amounts are stored as NUMERIC and written as decimal strings, so neither
+// direction goes through a float. A NUMERIC column carries no currency: every
+// row keeps its currency in a column of its own.
+
+// amount scans a NUMERIC column into dst in the currency *cur. Rows.Scan
+// fills destinations in order, so the currency column must come earlier in
+// the select list.
+type amount struct {
+	dst *money.Amount
+	cur *money.Currency
+}
+
+func (s amount) Scan(v any) error {
+	var digits string
+	switch v := v.(type) {
+	case []byte:
+		digits = string(v)
+	case string:
+		digits = v
+	default:
+		return fmt.Errorf("cannot scan %T into an amount", v)
+	}
+	a, err := money.Parse(digits, *s.cur)
+	if err != nil {
+		return fmt.Errorf("scanning amount %q: %w", digits, err)
+	}
+	*s.dst = a
+	return nil
+}
+
+// nullAmount is amount for nullable columns: NULL leaves *dst nil.
+type nullAmount struct {
+	dst **money.Amount
+	cur *money.Currency
+}
+
+func (s nullAmount) Scan(v any) error {
+	if v == nil {
+		*s.dst = nil
+		return nil
+	}
+	*s.dst = new(money.Amount)
+	return amount{dst: *s.dst, cur: s.cur}.Scan(v)
+}
+
+// rate scans a NUMERIC column into dst.
+type rate struct {
+	dst *money.Rate
+}
+
+func (s rate) Scan(v any) error {
+	b, ok := v.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into a rate", v)
+	}
+	r, err := money.ParseRate(string(b))
+	if err != nil {
+		return fmt.Errorf("scanning rate %q: %w", b, err)
+	}
+	*s.dst = r
+	return nil
+}
@@ -1,12 +1,32 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 
+	"banana-auction/internal/domain/auction"
 	"banana-auction/internal/domain/bid"
+	"banana-auction/internal/domain/money"
 )
 
+const bidColumns = `id, auction_id, buyer_id, currency, bid_price_per_kg, quantity_kg, is_proxy`
+
+func scanBid(row rowScanner) (bid.Bid, error) {
+	var b bid.Bid
+	var currency money.Currency
+	err := row.Scan(&b.ID, &b.AuctionID, &b.BuyerID, &currency, amount{&b.BidPricePerKG, &currency}, &b.QuantityKG, &b.Proxy)
+	return b, err
+}
+
+const proxyColumns = `id, auction_id, buyer_id, currency, max_price_per_kg, registered_at`
+
+func scanProxy(row rowScanner) (bid.ProxyBid, error) {
+	var p bid.ProxyBid
+	var currency money.Currency
+	err := row.Scan(&p.ID, &p.AuctionID, &p.BuyerID, &currency, amount{&p.MaxPricePerKG, &currency}, &p.RegisteredAt)
+	return p, err
+}
+
 type BidRepo struct {
 	db *sql.DB
 }
@@ -15,12 +35,18 @@ func NewBidRepo(db *sql.DB) *BidRepo {
 	return &BidRepo{db: db}
 }
 
-func (r *BidRepo) Create(b bid.Bid) (int, error) {
+func (r *BidRepo) Create(ctx context.Context, b bid.Bid) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return insertBid(ctx, conn(ctx, r.db), b)
+}
+
+func insertBid(ctx context.Context, q queryer, b bid.Bid) (int, error) {
 	var id int
-	err := r.db.QueryRow(`
-		INSERT INTO bids (auction_id, buyer_id, bid_price_per_kg)
-		VALUES ($1, $2, $3) RETURNING id`,
-		b.AuctionID, b.BuyerID, b.BidPricePerKG,
+	err := q.QueryRowContext(ctx, `
+		INSERT INTO bids (auction_id, buyer_id, currency, bid_price_per_kg, quantity_kg, is_proxy)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		b.AuctionID, b.BuyerID, b.BidPricePerKG.Currency(), b.BidPricePerKG.String(), b.QuantityKG, b.Proxy,
 	).Scan(&id)
 	if err != nil {
 		return 0, err
@@ -28,14 +54,34 @@ func (r *BidRepo) Create(b bid.Bid) (int, error) {
 	return id, nil
 }
 
-func (r *BidRepo) GetByID(id int) (bid.Bid, error) {
-	var b bid.Bid
-	err := r.db.QueryRow(`
-		SELECT id, auction_id, buyer_id, bid_price_per_kg
+func (r *BidRepo) WithAuctionLock(ctx context.Context, auctionID int, fn func(l bid.Locked) error) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Locking the auction row serializes concurrent bidders on the same auction.
+		a, err := scanAuction(tx.QueryRowContext(ctx, `
+			SELECT `+auctionColumns+`
+			FROM auctions WHERE id = $1 FOR UPDATE`, auctionID,
+		))
+		if err == sql.ErrNoRows {
+			return bid.ErrAuctionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return fn(&lockedAuction{ctx: ctx, tx: tx, auction: a})
+	})
+}
+
+func (r *BidRepo) GetByID(ctx context.Context, id int) (bid.Bid, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	b, err := scanBid(conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+bidColumns+`
 		FROM bids WHERE id = $1`, id,
-	).Scan(&b.ID, &b.AuctionID, &b.BuyerID, &b.BidPricePerKG)
+	))
 	if err == sql.ErrNoRows {
-		return bid.Bid{}, errors.New("bid not found")
+		return bid.Bid{}, bid.ErrNotFound
 	}
 	if err != nil {
 		return bid.Bid{}, err
@@ -43,23 +89,66 @@ func (r *BidRepo) GetByID(id int) (bid.Bid, error) {
 	return b, nil
 }
 
-func (r *BidRepo) Update(b bid.Bid) error {
-	_, err := r.db.Exec(`
-		UPDATE bids SET bid_price_per_kg = $1
-		WHERE id = $2`,
-		b.BidPricePerKG, b.ID,
+func (r *BidRepo) Highest(ctx context.Context, auctionID int) (*bid.Bid, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return highestBid(ctx, conn(ctx, r.db), auctionID)
+}
+
+func highestBid(ctx context.Context, q queryer, auctionID int) (*bid.Bid, error) {
+	b, err := scanBid(q.QueryRowContext(ctx, `
+		SELECT `+bidColumns+`
+		FROM bids WHERE auction_id = $1
+		ORDER BY bid_price_per_kg DESC, id ASC LIMIT 1`, auctionID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *BidRepo) GetProxy(ctx context.Context, auctionID, buyerID int) (bid.ProxyBid, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	p, err := scanProxy(conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+proxyColumns+`
+		FROM proxy_bids WHERE auction_id = $1 AND buyer_id = $2`, auctionID, buyerID,
+	))
+	if err == sql.ErrNoRows {
+		return bid.ProxyBid{}, bid.ErrProxyNotFound
+	}
+	if err != nil {
+		return bid.ProxyBid{}, err
+	}
+	return p, nil
+}
+
+func (r *BidRepo) Update(ctx context.Context, b bid.Bid) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE bids SET currency = $1, bid_price_per_kg = $2
+		WHERE id = $3`,
+		b.BidPricePerKG.Currency(), b.BidPricePerKG.String(), b.ID,
 	)
 	return err
 }
 
-func (r *BidRepo) Delete(id int) error {
-	_, err := r.db.Exec(`DELETE FROM bids WHERE id = $1`, id)
+func (r *BidRepo) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM bids WHERE id = $1`, id)
 	return err
 }
 
-func (r *BidRepo) ListByAuctionID(auctionID int) ([]bid.Bid, error) {
-	rows, err := r.db.Query(`
-		SELECT id, auction_id, buyer_id, bid_price_per_kg
+func (r *BidRepo) ListByAuctionID(ctx context.Context, auctionID int) ([]bid.Bid, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+bidColumns+`
 		FROM bids WHERE auction_id = $1`, auctionID)
 	if err != nil {
 		return nil, err
@@ -68,11 +157,188 @@ func (r *BidRepo) ListByAuctionID(auctionID int) ([]bid.Bid, error) {
 
 	var bids []bid.Bid
 	for rows.Next() {
-		var b bid.Bid
-		if err := rows.Scan(&b.ID, &b.AuctionID, &b.BuyerID, &b.BidPricePerKG); err != nil {
+		b, err := scanBid(rows)
+		if err != nil {
 			return nil, err
 		}
 		bids = append(bids, b)
 	}
-	return bids, nil
+	return bids, rows.Err()
+}
+
+func (r *BidRepo) ListByBuyer(ctx context.Context, buyerID int) ([]bid.Summary, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT a.id, a.auction_type, a.status, a.cancel_reason, `+auctionEndsAtSQL+` AS ends_at,
+			best.id, best.auction_id, best.buyer_id, best.currency, best.bid_price_per_kg, best.quantity_kg, best.is_proxy,
+			mine.bid_count,
+			CASE WHEN a.status NOT IN ('scheduled', 'live') OR a.auction_type = 'multi_unit'
+					OR a.auction_type IN `+sealedTypesSQL+` THEN NULL
+				ELSE top.buyer_id = $1
+			END,
+			a.status = 'settled' AND COALESCE(st.winner_id = $1 OR EXISTS (
+				SELECT 1 FROM settlement_allocations sa WHERE sa.settlement_id = st.id AND sa.buyer_id = $1
+			), FALSE)
+		FROM (
+			SELECT auction_id, COUNT(*) AS bid_count FROM bids WHERE buyer_id = $1 GROUP BY auction_id
+		) mine
+		JOIN auctions a ON a.id = mine.auction_id
+		CROSS JOIN LATERAL (
+			SELECT `+bidColumns+` FROM bids
+			WHERE auction_id = a.id AND buyer_id = $1
+			ORDER BY bid_price_per_kg DESC, id ASC LIMIT 1
+		) best
+		CROSS JOIN LATERAL (
+			SELECT buyer_id FROM bids
+			WHERE auction_id = a.id
+			ORDER BY bid_price_per_kg DESC, id ASC LIMIT 1
+		) top
+		LEFT JOIN settlements st ON st.auction_id = a.id
+		ORDER BY ends_at DESC, a.id DESC`, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []bid.Summary
+	for rows.Next() {
+		var s bid.Summary
+		var leading sql.NullBool
+		var currency money.Currency
+		b := &s.BestBid
+		err := rows.Scan(&s.AuctionID, &s.AuctionType, &s.AuctionStatus, &s.CancelReason, &s.EndsAt,
+			&b.ID, &b.AuctionID, &b.BuyerID, &currency, amount{&b.BidPricePerKG, &currency}, &b.QuantityKG, &b.Proxy,
+			&s.BidCount, &leading, &s.Won)
+		if err != nil {
+			return nil, err
+		}
+		if leading.Valid {
+			s.Leading = &leading.Bool
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+// lockedAuction implements bid.Locked inside WithAuctionLock's transaction.
+// ctx is the one the transaction was started with.
+type lockedAuction struct {
+	ctx     context.Context
+	tx      *sql.Tx
+	auction auction.Auction
+}
+
+func (l *lockedAuction) Auction() auction.Auction {
+	return l.auction
+}
+
+func (l *lockedAuction) Highest() (*bid.Bid, error) {
+	return highestBid(l.ctx, l.tx, l.auction.ID)
+}
+
+func (l *lockedAuction) Proxies() ([]bid.ProxyBid, error) {
+	rows, err := l.tx.QueryContext(l.ctx, `
+		SELECT `+proxyColumns+`
+		FROM proxy_bids WHERE auction_id = $1
+		ORDER BY registered_at, id`, l.auction.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var proxies []bid.ProxyBid
+	for rows.Next() {
+		p, err := scanProxy(rows)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, p)
+	}
+	return proxies, rows.Err()
+}
+
+func (l *lockedAuction) CreateBid(b bid.Bid) (int, error) {
+	return insertBid(l.ctx, l.tx, b)
+}
+
+func (l *lockedAuction) BuyerBid(buyerID int) (*bid.Bid, error) {
+	b, err := scanBid(l.tx.QueryRowContext(l.ctx, `
+		SELECT `+bidColumns+`
+		FROM bids WHERE auction_id = $1 AND buyer_id = $2
+		ORDER BY bid_price_per_kg DESC, id ASC LIMIT 1`, l.auction.ID, buyerID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (l *lockedAuction) DeleteBid(id int) error {
+	_, err := l.tx.ExecContext(l.ctx, `DELETE FROM bids WHERE id = $1 AND auction_id = $2`, id, l.auction.ID)
+	return err
+}
+
+func (l *lockedAuction) SaveProxy(p bid.ProxyBid) (int, error) {
+	var id int
+	err := l.tx.QueryRowContext(l.ctx, `
+		INSERT INTO proxy_bids (auction_id, buyer_id, currency, max_price_per_kg)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (auction_id, buyer_id) DO UPDATE
+		SET currency = EXCLUDED.currency, max_price_per_kg = EXCLUDED.max_price_per_kg,
+			registered_at = CASE
+				WHEN proxy_bids.max_price_per_kg = EXCLUDED.max_price_per_kg THEN proxy_bids.registered_at
+				ELSE NOW()
+			END
+		RETURNING id`,
+		p.AuctionID, p.BuyerID, p.MaxPricePerKG.Currency(), p.MaxPricePerKG.String(),
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (l *lockedAuction) Extend(minutes int) (auction.Auction, error) {
+	_, err := l.tx.ExecContext(l.ctx, `
+		UPDATE auctions SET extension_minutes = extension_minutes + $1
+		WHERE id = $2`, minutes, l.auction.ID)
+	if err != nil {
+		return auction.Auction{}, err
+	}
+	l.auction.ExtensionMinutes += minutes
+	return l.auction, nil
+}
+
+func (l *lockedAuction) LotWeightKG() (int, error) {
+	var weight int
+	err := l.tx.QueryRowContext(l.ctx, `SELECT total_weight_kg FROM lots WHERE id = $1`, l.auction.LotID).Scan(&weight)
+	return weight, err
+}
+
+// Close moves the auction from live to closed. The update is conditional on
+// the status it was locked with, like AuctionRepo.Transition.
+func (l *lockedAuction) Close(winningBidID int) (auction.Auction, error) {
+	if !l.auction.Status.CanTransitionTo(auction.StatusClosed) {
+		return auction.Auction{}, auction.ErrInvalidTransition
+	}
+	res, err := l.tx.ExecContext(l.ctx, `
+		UPDATE auctions SET status = $1, closed_at = NOW(), winning_bid_id = $2
+		WHERE id = $3 AND status = $4`, auction.StatusClosed, winningBidID, l.auction.ID, l.auction.Status)
+	if err != nil {
+		return auction.Auction{}, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return auction.Auction{}, err
+	}
+	if n != 1 {
+		return auction.Auction{}, auction.ErrInvalidTransition
+	}
+	l.auction.Status = auction.StatusClosed
+	l.auction.WinningBidID = &winningBidID
+	return l.auction, nil
 }
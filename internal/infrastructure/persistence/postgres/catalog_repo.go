@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"banana-auction/internal/domain/catalog"
+)
+
+// catalogQuery computes each open auction's start, end and current price in
+// SQL so that filters, sorting and the cursor can all be applied there. The
+// schedule and price rules mirror auction.Auction's EndTime and PriceAt.
+const catalogQuery = `
+WITH open_auctions AS (
+	SELECT a.id, a.lot_id, a.auction_type, a.status, a.currency, a.initial_price_per_kg, a.buy_now_price_per_kg,
+		a.reserve_price_per_kg, a.dutch_floor_price_per_kg, a.dutch_decrement_per_kg, a.dutch_decrement_interval_minutes,
+		` + auctionStartsAtSQL + ` AS starts_at,
+		` + auctionEndsAtSQL + ` AS ends_at,
+		(SELECT MAX(b.bid_price_per_kg) FROM bids b WHERE b.auction_id = a.id) AS highest,
+		l.cultivar, l.planted_country, l.harvest_date, l.total_weight_kg
+	FROM auctions a
+	JOIN lots l ON l.id = a.lot_id
+	WHERE a.status IN ('scheduled', 'live')
+), listings AS (
+	SELECT *,
+		CASE
+			WHEN auction_type = 'dutch' THEN GREATEST(dutch_floor_price_per_kg,
+				initial_price_per_kg - dutch_decrement_per_kg * FLOOR(
+					GREATEST(EXTRACT(EPOCH FROM NOW() - starts_at), 0) / (NULLIF(dutch_decrement_interval_minutes, 0) * 60)
+				))
+			WHEN auction_type IN ` + sealedTypesSQL + ` THEN initial_price_per_kg
+			ELSE COALESCE(highest, initial_price_per_kg)
+		END AS current_price,
+		CASE
+			WHEN reserve_price_per_kg = 0 OR auction_type IN ` + sealedTypesSQL + ` THEN NULL
+			ELSE COALESCE(highest, 0) >= reserve_price_per_kg
+		END AS reserve_met
+	FROM open_auctions
+)
+SELECT id, lot_id, auction_type, status, starts_at, ends_at, currency, initial_price_per_kg, current_price,
+	buy_now_price_per_kg, reserve_met, cultivar, planted_country, harvest_date, total_weight_kg
+FROM listings
+WHERE ends_at > NOW()`
+
+type CatalogRepo struct {
+	db *sql.DB
+}
+
+func NewCatalogRepo(db *sql.DB) *CatalogRepo {
+	return &CatalogRepo{db: db}
+}
+
+func (r *CatalogRepo) Search(ctx context.Context, f catalog.Filter, after *catalog.Cursor, limit int) ([]catalog.Listing, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var where []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Cultivar != "" {
+		where = append(where, "lower(cultivar) = lower("+arg(f.Cultivar)+")")
+	}
+	if f.PlantedCountry != "" {
+		where = append(where, "lower(planted_country) = lower("+arg(f.PlantedCountry)+")")
+	}
+	if !f.HarvestFrom.IsZero() {
+		where = append(where, "harvest_date >= "+arg(f.HarvestFrom)+"::date")
+	}
+	if !f.HarvestTo.IsZero() {
+		where = append(where, "harvest_date <= "+arg(f.HarvestTo)+"::date")
+	}
+	if f.MinWeightKG > 0 {
+		where = append(where, "total_weight_kg >= "+arg(f.MinWeightKG))
+	}
+	if f.MaxWeightKG > 0 {
+		where = append(where, "total_weight_kg <= "+arg(f.MaxWeightKG))
+	}
+	if p := f.MinPricePerKG; !p.IsZero() {
+		where = append(where, "currency = "+arg(p.Currency())+" AND current_price >= "+arg(p.String())+"::numeric")
+	}
+	if p := f.MaxPricePerKG; !p.IsZero() {
+		where = append(where, "currency = "+arg(p.Currency())+" AND current_price <= "+arg(p.String())+"::numeric")
+	}
+
+	var order string
+	switch f.Sort {
+	case catalog.SortPriceAsc:
+		order = "currency ASC, current_price ASC, id ASC"
+		if after != nil {
+			where = append(where, "(currency, current_price, id) > ("+arg(after.PricePerKG.Currency())+"::text, "+
+				arg(after.PricePerKG.String())+"::numeric, "+arg(after.AuctionID)+"::int)")
+		}
+	case catalog.SortPriceDesc:
+		order = "currency ASC, current_price DESC, id ASC"
+		if after != nil {
+			c, p, id := arg(after.PricePerKG.Currency())+"::text", arg(after.PricePerKG.String())+"::numeric", arg(after.AuctionID)+"::int"
+			where = append(where, "(currency > "+c+" OR (currency = "+c+" AND (current_price < "+p+
+				" OR (current_price = "+p+" AND id > "+id+"))))")
+		}
+	default:
+		order = "ends_at ASC, id ASC"
+		if after != nil {
+			where = append(where, "(ends_at, id) > ("+arg(after.EndsAt)+"::timestamptz, "+arg(after.AuctionID)+"::int)")
+		}
+	}
+
+	query := catalogQuery
+	if len(where) > 0 {
+		query += "\n\tAND " + strings.Join(where, "\n\tAND ")
+	}
+	query += "\nORDER BY " + order + "\nLIMIT " + arg(limit)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listings []catalog.Listing
+	for rows.Next() {
+		var l catalog.Listing
+		var reserveMet sql.NullBool
+		err := rows.Scan(&l.AuctionID, &l.LotID, &l.Type, &l.Status, &l.StartsAt, &l.EndsAt, &l.Currency,
+			amount{&l.InitialPricePerKG, &l.Currency}, amount{&l.CurrentPricePerKG, &l.Currency},
+			amount{&l.BuyNowPricePerKG, &l.Currency}, &reserveMet,
+			&l.Cultivar, &l.PlantedCountry, &l.HarvestDate, &l.TotalWeightKG)
+		if err != nil {
+			return nil, err
+		}
+		if reserveMet.Valid {
+			l.ReserveMet = &reserveMet.Bool
+		}
+		listings = append(listings, l)
+	}
+	return listings, rows.Err()
+}
@@ -1,9 +1,12 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"banana-auction/config"
 
@@ -13,9 +16,14 @@ import (
 
 var (
 	db *sql.DB
+
+	// queryTimeout bounds every repository call; zero means no limit
+	// beyond the caller's own context.
+	queryTimeout time.Duration
 )
 
-func InitDB(cfg *config.Config) error {
+// Connect opens the database connection without touching the schema.
+func Connect(cfg *config.Config) error {
 	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		cfg.DbHost, cfg.DbPort, cfg.DbUser, cfg.DbPassword, cfg.DbName)
 
@@ -25,51 +33,55 @@ func InitDB(cfg *config.Config) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	queryTimeout = cfg.DBTimeout
+
 	if err := db.Ping(); err != nil {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS users (
-			id SERIAL PRIMARY KEY,
-			username TEXT UNIQUE NOT NULL,
-			password_hash TEXT NOT NULL,
-			name TEXT NOT NULL,
-			role TEXT NOT NULL CHECK (role IN ('seller', 'buyer'))
-		);
-		CREATE TABLE IF NOT EXISTS lots (
-			id SERIAL PRIMARY KEY,
-			seller_id INTEGER REFERENCES users(id),
-			cultivar TEXT NOT NULL,
-			planted_country TEXT NOT NULL,
-			harvest_date TEXT NOT NULL,
-			total_weight_kg INTEGER NOT NULL
-		);
-		CREATE TABLE IF NOT EXISTS auctions (
-			id SERIAL PRIMARY KEY,
-			lot_id INTEGER REFERENCES lots(id),
-			start_date TEXT NOT NULL,
-			duration_days INTEGER NOT NULL,
-			initial_price_per_kg FLOAT NOT NULL
-		);
-		CREATE TABLE IF NOT EXISTS bids (
-			id SERIAL PRIMARY KEY,
-			auction_id INTEGER REFERENCES auctions(id),
-			buyer_id INTEGER REFERENCES users(id),
-			bid_price_per_kg FLOAT NOT NULL
-		);
-	`)
+	return nil
+}
+
+// InitDB connects and applies any pending migrations.
+func InitDB(cfg *config.Config) error {
+	if err := Connect(cfg); err != nil {
+		return err
+	}
+
+	applied, err := MigrateUp(db)
 	if err != nil {
-		return fmt.Errorf("failed to create tables: %w", err)
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if applied > 0 {
+		log.Printf("Applied %d database migrations", applied)
 	}
 
 	return nil
 }
 
+// withTimeout derives the context for one repository call from the caller's,
+// so a cancelled request or a stuck query never holds a connection for
+// longer than the configured DB timeout.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
 func GetDB() *sql.DB {
 	return db
 }
 
 func IsDuplicateKeyError(err error) bool {
-	return errors.Is(err, &pq.Error{Code: "23505"}) // PostgreSQL unique violation code
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" // PostgreSQL unique violation code
+}
+
+// IsSerializationError reports whether the database aborted a transaction
+// because it conflicted with a concurrent one, in which case running it
+// again can succeed.
+func IsSerializationError(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == "40001" || pqErr.Code == "40P01") // serialization_failure, deadlock_detected
 }
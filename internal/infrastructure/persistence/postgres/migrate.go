@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the pg_advisory_lock key that serializes migrations
+// across replicas.
+const migrationLockID = 4242001
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// loadMigrations reads the embedded NNNN_name.up.sql / NNNN_name.down.sql
+// pairs, sorted by version.
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		file := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(file, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(file, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("unexpected migration file %s", file)
+		}
+
+		base := strings.TrimSuffix(file, "."+direction+".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration file %s must be named NNNN_name.%s.sql", file, direction)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("migration file %s has an invalid version", file)
+		}
+
+		body, err := migrationFiles.ReadFile(path.Join("migrations", file))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %d has mismatched names %s and %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d is missing its up or down step", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// withMigrationLock runs fn on a single connection holding the migration
+// advisory lock, after making sure schema_migrations exists.
+func withMigrationLock(db *sql.DB, fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func appliedVersions(conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(context.Background(), `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// MigrateUp applies every pending migration in order and returns how many
+// were applied. Each migration runs in its own transaction.
+func MigrateUp(db *sql.DB) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = withMigrationLock(db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if err := runMigration(conn, m.Up, func(tx *sql.Tx) error {
+				_, err := tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %04d_%s up: %w", m.Version, m.Name, err)
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// MigrateDown rolls back the latest steps applied migrations and returns how
+// many were rolled back.
+func MigrateDown(db *sql.DB, steps int) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = withMigrationLock(db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+			m := migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if err := runMigration(conn, m.Down, func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %04d_%s down: %w", m.Version, m.Name, err)
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// MigrationStatuses lists every known migration and when it was applied.
+func MigrationStatuses(db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	err = withMigrationLock(db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			s := MigrationStatus{Version: m.Version, Name: m.Name}
+			if at, ok := applied[m.Version]; ok {
+				s.AppliedAt = &at
+			}
+			statuses = append(statuses, s)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+func runMigration(conn *sql.Conn, script string, record func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS bids;
+DROP TABLE IF EXISTS auctions;
+DROP TABLE IF EXISTS lots;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,33 @@
+-- IF NOT EXISTS lets databases created before migrations existed adopt this
+-- version without changes.
+CREATE TABLE IF NOT EXISTS users (
+	id SERIAL PRIMARY KEY,
+	username TEXT UNIQUE NOT NULL,
+	password_hash TEXT NOT NULL,
+	name TEXT NOT NULL,
+	role TEXT NOT NULL CHECK (role IN ('seller', 'buyer'))
+);
+
+CREATE TABLE IF NOT EXISTS lots (
+	id SERIAL PRIMARY KEY,
+	seller_id INTEGER REFERENCES users(id),
+	cultivar TEXT NOT NULL,
+	planted_country TEXT NOT NULL,
+	harvest_date TEXT NOT NULL,
+	total_weight_kg INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS auctions (
+	id SERIAL PRIMARY KEY,
+	lot_id INTEGER REFERENCES lots(id),
+	start_date TEXT NOT NULL,
+	duration_days INTEGER NOT NULL,
+	initial_price_per_kg FLOAT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS bids (
+	id SERIAL PRIMARY KEY,
+	auction_id INTEGER REFERENCES auctions(id),
+	buyer_id INTEGER REFERENCES users(id),
+	bid_price_per_kg FLOAT NOT NULL
+);
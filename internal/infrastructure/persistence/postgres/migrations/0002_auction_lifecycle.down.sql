@@ -0,0 +1,3 @@
+ALTER TABLE auctions DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE auctions DROP COLUMN IF EXISTS winning_bid_id;
+ALTER TABLE auctions DROP COLUMN IF EXISTS status;
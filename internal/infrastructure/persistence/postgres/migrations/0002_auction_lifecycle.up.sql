@@ -0,0 +1,6 @@
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'scheduled';
+ALTER TABLE auctions DROP CONSTRAINT IF EXISTS auctions_status_check;
+ALTER TABLE auctions ADD CONSTRAINT auctions_status_check
+	CHECK (status IN ('scheduled', 'live', 'closed', 'cancelled', 'settled', 'unsold'));
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS winning_bid_id INTEGER REFERENCES bids(id);
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
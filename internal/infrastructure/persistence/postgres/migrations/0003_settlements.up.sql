@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS settlements (
+	id SERIAL PRIMARY KEY,
+	auction_id INTEGER UNIQUE NOT NULL REFERENCES auctions(id),
+	outcome TEXT NOT NULL CHECK (outcome IN ('sold', 'unsold')),
+	winner_id INTEGER REFERENCES users(id),
+	winning_bid_id INTEGER REFERENCES bids(id),
+	clearing_price_per_kg FLOAT NOT NULL,
+	total_weight_kg INTEGER NOT NULL,
+	total_amount FLOAT NOT NULL,
+	commission_rate FLOAT NOT NULL,
+	commission FLOAT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
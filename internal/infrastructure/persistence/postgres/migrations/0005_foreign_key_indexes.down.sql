@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS bids_auction_id_price_idx;
+DROP INDEX IF EXISTS auctions_status_idx;
+DROP INDEX IF EXISTS auctions_lot_id_idx;
+DROP INDEX IF EXISTS lots_seller_id_idx;
@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS lots_seller_id_idx ON lots (seller_id);
+CREATE INDEX IF NOT EXISTS auctions_lot_id_idx ON auctions (lot_id);
+CREATE INDEX IF NOT EXISTS auctions_status_idx ON auctions (status);
+CREATE INDEX IF NOT EXISTS bids_auction_id_price_idx ON bids (auction_id, bid_price_per_kg DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS proxy_bids;
+ALTER TABLE bids DROP COLUMN IF EXISTS is_proxy;
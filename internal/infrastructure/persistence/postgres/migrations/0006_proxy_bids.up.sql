@@ -0,0 +1,10 @@
+ALTER TABLE bids ADD COLUMN is_proxy BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE proxy_bids (
+	id SERIAL PRIMARY KEY,
+	auction_id INTEGER NOT NULL REFERENCES auctions(id),
+	buyer_id INTEGER NOT NULL REFERENCES users(id),
+	max_price_per_kg FLOAT NOT NULL,
+	registered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE (auction_id, buyer_id)
+);
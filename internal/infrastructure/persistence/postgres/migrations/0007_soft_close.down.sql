@@ -0,0 +1,5 @@
+ALTER TABLE auctions
+	DROP COLUMN IF EXISTS extension_minutes,
+	DROP COLUMN IF EXISTS soft_close_max_extension_minutes,
+	DROP COLUMN IF EXISTS soft_close_extension_minutes,
+	DROP COLUMN IF EXISTS soft_close_window_minutes;
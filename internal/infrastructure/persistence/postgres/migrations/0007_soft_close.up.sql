@@ -0,0 +1,5 @@
+ALTER TABLE auctions
+	ADD COLUMN soft_close_window_minutes INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN soft_close_extension_minutes INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN soft_close_max_extension_minutes INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN extension_minutes INTEGER NOT NULL DEFAULT 0;
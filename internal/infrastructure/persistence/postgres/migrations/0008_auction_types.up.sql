@@ -0,0 +1,3 @@
+ALTER TABLE auctions ADD COLUMN auction_type TEXT NOT NULL DEFAULT 'english'
+	CONSTRAINT auctions_auction_type_check
+	CHECK (auction_type IN ('english', 'sealed_first_price', 'sealed_second_price'));
@@ -0,0 +1,9 @@
+ALTER TABLE auctions
+	DROP COLUMN IF EXISTS dutch_decrement_interval_minutes,
+	DROP COLUMN IF EXISTS dutch_decrement_per_kg,
+	DROP COLUMN IF EXISTS dutch_floor_price_per_kg;
+
+UPDATE auctions SET auction_type = 'english' WHERE auction_type = 'dutch';
+ALTER TABLE auctions DROP CONSTRAINT auctions_auction_type_check;
+ALTER TABLE auctions ADD CONSTRAINT auctions_auction_type_check
+	CHECK (auction_type IN ('english', 'sealed_first_price', 'sealed_second_price'));
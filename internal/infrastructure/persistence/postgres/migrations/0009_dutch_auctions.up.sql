@@ -0,0 +1,8 @@
+ALTER TABLE auctions DROP CONSTRAINT auctions_auction_type_check;
+ALTER TABLE auctions ADD CONSTRAINT auctions_auction_type_check
+	CHECK (auction_type IN ('english', 'sealed_first_price', 'sealed_second_price', 'dutch'));
+
+ALTER TABLE auctions
+	ADD COLUMN dutch_floor_price_per_kg FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN dutch_decrement_per_kg FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN dutch_decrement_interval_minutes INTEGER NOT NULL DEFAULT 0;
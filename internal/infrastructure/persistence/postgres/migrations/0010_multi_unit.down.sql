@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS settlement_allocations;
+
+ALTER TABLE bids DROP COLUMN IF EXISTS quantity_kg;
+
+ALTER TABLE auctions DROP COLUMN IF EXISTS clearing;
+UPDATE auctions SET auction_type = 'english' WHERE auction_type = 'multi_unit';
+ALTER TABLE auctions DROP CONSTRAINT auctions_auction_type_check;
+ALTER TABLE auctions ADD CONSTRAINT auctions_auction_type_check
+	CHECK (auction_type IN ('english', 'sealed_first_price', 'sealed_second_price', 'dutch'));
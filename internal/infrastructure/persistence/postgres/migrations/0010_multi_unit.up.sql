@@ -0,0 +1,20 @@
+ALTER TABLE auctions DROP CONSTRAINT auctions_auction_type_check;
+ALTER TABLE auctions ADD CONSTRAINT auctions_auction_type_check
+	CHECK (auction_type IN ('english', 'sealed_first_price', 'sealed_second_price', 'dutch', 'multi_unit'));
+ALTER TABLE auctions ADD COLUMN clearing TEXT NOT NULL DEFAULT ''
+	CHECK (clearing IN ('', 'uniform', 'pay_as_bid'));
+
+ALTER TABLE bids ADD COLUMN quantity_kg INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE settlement_allocations (
+	id SERIAL PRIMARY KEY,
+	settlement_id INTEGER NOT NULL REFERENCES settlements(id) ON DELETE CASCADE,
+	bid_id INTEGER NOT NULL REFERENCES bids(id),
+	buyer_id INTEGER NOT NULL REFERENCES users(id),
+	bid_quantity_kg INTEGER NOT NULL,
+	quantity_kg INTEGER NOT NULL,
+	price_per_kg FLOAT NOT NULL,
+	amount FLOAT NOT NULL
+);
+
+CREATE INDEX settlement_allocations_settlement_id_idx ON settlement_allocations (settlement_id);
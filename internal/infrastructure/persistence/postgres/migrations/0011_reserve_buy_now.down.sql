@@ -0,0 +1,3 @@
+ALTER TABLE auctions
+	DROP COLUMN IF EXISTS buy_now_price_per_kg,
+	DROP COLUMN IF EXISTS reserve_price_per_kg;
@@ -0,0 +1,3 @@
+ALTER TABLE auctions
+	ADD COLUMN reserve_price_per_kg FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN buy_now_price_per_kg FLOAT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE auctions
+	DROP COLUMN IF EXISTS cancelled_at,
+	DROP COLUMN IF EXISTS cancel_reason;
@@ -0,0 +1,3 @@
+ALTER TABLE auctions
+	ADD COLUMN cancel_reason TEXT NOT NULL DEFAULT '',
+	ADD COLUMN cancelled_at TIMESTAMPTZ;
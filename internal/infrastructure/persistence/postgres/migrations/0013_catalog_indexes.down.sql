@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS lots_harvest_date_idx;
+DROP INDEX IF EXISTS lots_planted_country_idx;
+DROP INDEX IF EXISTS lots_cultivar_idx;
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS lots_cultivar_idx ON lots (lower(cultivar));
+CREATE INDEX IF NOT EXISTS lots_planted_country_idx ON lots (lower(planted_country));
+CREATE INDEX IF NOT EXISTS lots_harvest_date_idx ON lots (harvest_date);
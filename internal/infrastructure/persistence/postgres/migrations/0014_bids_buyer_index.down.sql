@@ -0,0 +1 @@
+DROP INDEX IF EXISTS bids_buyer_id_auction_id_idx;
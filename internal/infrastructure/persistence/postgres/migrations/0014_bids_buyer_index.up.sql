@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS bids_buyer_id_auction_id_idx ON bids (buyer_id, auction_id);
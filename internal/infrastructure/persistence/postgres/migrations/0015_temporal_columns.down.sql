@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS auctions_start_date_idx;
+
+ALTER TABLE auctions ALTER COLUMN start_date TYPE TEXT
+	USING to_char(start_date AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"');
+ALTER TABLE lots ALTER COLUMN harvest_date TYPE TEXT USING to_char(harvest_date, 'YYYY-MM-DD');
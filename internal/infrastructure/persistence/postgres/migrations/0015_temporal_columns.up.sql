@@ -0,0 +1,57 @@
+-- Harvest and start dates used to be free-form text. They become DATE and
+-- TIMESTAMPTZ, read the way the API used to read them: a harvest date is its
+-- leading YYYY-MM-DD, a start is an RFC 3339 timestamp or a bare date taken
+-- as midnight UTC. If any row holds something else nothing is converted and
+-- the error lists those rows so they can be fixed before migrating again.
+
+CREATE FUNCTION pg_temp.parse_harvest_date(s TEXT) RETURNS DATE
+LANGUAGE plpgsql AS $$
+BEGIN
+	IF s ~ '^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}))?$' THEN
+		RETURN left(s, 10)::date;
+	END IF;
+	RETURN NULL;
+EXCEPTION WHEN others THEN
+	RETURN NULL;
+END
+$$;
+
+CREATE FUNCTION pg_temp.parse_start_date(s TEXT) RETURNS TIMESTAMPTZ
+LANGUAGE plpgsql AS $$
+BEGIN
+	IF s ~ '^\d{4}-\d{2}-\d{2}$' THEN
+		RETURN s::timestamp AT TIME ZONE 'UTC';
+	ELSIF s ~ '^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$' THEN
+		RETURN s::timestamptz;
+	END IF;
+	RETURN NULL;
+EXCEPTION WHEN others THEN
+	RETURN NULL;
+END
+$$;
+
+DO $$
+DECLARE
+	bad TEXT;
+BEGIN
+	SELECT string_agg(problem, E'\n' ORDER BY kind, id) INTO bad
+	FROM (
+		SELECT 'lot' AS kind, id, format('lot %s: harvest_date %L', id, harvest_date) AS problem
+		FROM lots WHERE pg_temp.parse_harvest_date(harvest_date) IS NULL
+		UNION ALL
+		SELECT 'auction', id, format('auction %s: start_date %L', id, start_date)
+		FROM auctions WHERE pg_temp.parse_start_date(start_date) IS NULL
+	) p;
+	IF bad IS NOT NULL THEN
+		RAISE EXCEPTION 'cannot convert these dates, fix them and migrate again:%', E'\n' || bad;
+	END IF;
+END
+$$;
+
+ALTER TABLE lots ALTER COLUMN harvest_date TYPE DATE USING pg_temp.parse_harvest_date(harvest_date);
+ALTER TABLE auctions ALTER COLUMN start_date TYPE TIMESTAMPTZ USING pg_temp.parse_start_date(start_date);
+
+CREATE INDEX IF NOT EXISTS auctions_start_date_idx ON auctions (start_date);
+
+DROP FUNCTION pg_temp.parse_harvest_date(TEXT);
+DROP FUNCTION pg_temp.parse_start_date(TEXT);
@@ -0,0 +1,26 @@
+ALTER TABLE settlement_allocations
+	ALTER COLUMN price_per_kg TYPE FLOAT,
+	ALTER COLUMN amount TYPE FLOAT;
+
+ALTER TABLE settlements
+	DROP COLUMN currency,
+	ALTER COLUMN clearing_price_per_kg TYPE FLOAT,
+	ALTER COLUMN total_amount TYPE FLOAT,
+	ALTER COLUMN commission_rate TYPE FLOAT,
+	ALTER COLUMN commission TYPE FLOAT;
+
+ALTER TABLE proxy_bids
+	DROP COLUMN currency,
+	ALTER COLUMN max_price_per_kg TYPE FLOAT;
+
+ALTER TABLE bids
+	DROP COLUMN currency,
+	ALTER COLUMN bid_price_per_kg TYPE FLOAT;
+
+ALTER TABLE auctions
+	DROP COLUMN currency,
+	ALTER COLUMN initial_price_per_kg TYPE FLOAT,
+	ALTER COLUMN reserve_price_per_kg TYPE FLOAT,
+	ALTER COLUMN buy_now_price_per_kg TYPE FLOAT,
+	ALTER COLUMN dutch_floor_price_per_kg TYPE FLOAT,
+	ALTER COLUMN dutch_decrement_per_kg TYPE FLOAT;
@@ -0,0 +1,37 @@
+-- Prices and amounts used to be FLOAT. They become exact NUMERIC values with
+-- a currency on every row that holds one. Every existing row was priced in
+-- US dollars, so it gets USD and its amounts are rounded to the cent, which
+-- is what they were meant to hold.
+
+ALTER TABLE auctions ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE auctions ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE auctions
+	ALTER COLUMN initial_price_per_kg TYPE NUMERIC(18,4) USING ROUND(initial_price_per_kg::numeric, 2),
+	ALTER COLUMN reserve_price_per_kg TYPE NUMERIC(18,4) USING ROUND(reserve_price_per_kg::numeric, 2),
+	ALTER COLUMN buy_now_price_per_kg TYPE NUMERIC(18,4) USING ROUND(buy_now_price_per_kg::numeric, 2),
+	ALTER COLUMN dutch_floor_price_per_kg TYPE NUMERIC(18,4) USING ROUND(dutch_floor_price_per_kg::numeric, 2),
+	ALTER COLUMN dutch_decrement_per_kg TYPE NUMERIC(18,4) USING ROUND(dutch_decrement_per_kg::numeric, 2);
+
+ALTER TABLE bids ADD COLUMN currency CHAR(3);
+UPDATE bids b SET currency = a.currency FROM auctions a WHERE a.id = b.auction_id;
+ALTER TABLE bids
+	ALTER COLUMN currency SET NOT NULL,
+	ALTER COLUMN bid_price_per_kg TYPE NUMERIC(18,4) USING ROUND(bid_price_per_kg::numeric, 2);
+
+ALTER TABLE proxy_bids ADD COLUMN currency CHAR(3);
+UPDATE proxy_bids p SET currency = a.currency FROM auctions a WHERE a.id = p.auction_id;
+ALTER TABLE proxy_bids
+	ALTER COLUMN currency SET NOT NULL,
+	ALTER COLUMN max_price_per_kg TYPE NUMERIC(18,4) USING ROUND(max_price_per_kg::numeric, 2);
+
+ALTER TABLE settlements ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE settlements ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE settlements
+	ALTER COLUMN clearing_price_per_kg TYPE NUMERIC(18,4) USING ROUND(clearing_price_per_kg::numeric, 2),
+	ALTER COLUMN total_amount TYPE NUMERIC(18,4) USING ROUND(total_amount::numeric, 2),
+	ALTER COLUMN commission_rate TYPE NUMERIC(7,6) USING ROUND(commission_rate::numeric, 6),
+	ALTER COLUMN commission TYPE NUMERIC(18,4) USING ROUND(commission::numeric, 2);
+
+ALTER TABLE settlement_allocations
+	ALTER COLUMN price_per_kg TYPE NUMERIC(18,4) USING ROUND(price_per_kg::numeric, 2),
+	ALTER COLUMN amount TYPE NUMERIC(18,4) USING ROUND(amount::numeric, 2);
@@ -0,0 +1,7 @@
+ALTER TABLE settlements
+	DROP COLUMN fx_base,
+	DROP COLUMN fx_quote,
+	DROP COLUMN fx_rate,
+	DROP COLUMN fx_rate_updated_at;
+
+DROP TABLE fx_rates;
@@ -0,0 +1,18 @@
+-- The exchange rate table behind indicative prices. Each pair is stored in
+-- one direction: one unit of base is worth rate units of quote.
+CREATE TABLE fx_rates (
+	base CHAR(3) NOT NULL,
+	quote CHAR(3) NOT NULL,
+	rate NUMERIC(18,6) NOT NULL CHECK (rate > 0),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (base, quote),
+	CHECK (base <> quote)
+);
+
+-- The rate between a settlement's currency and the home currency as it
+-- stood when the auction settled. NULL when none was needed or on file.
+ALTER TABLE settlements
+	ADD COLUMN fx_base CHAR(3),
+	ADD COLUMN fx_quote CHAR(3),
+	ADD COLUMN fx_rate NUMERIC(18,6),
+	ADD COLUMN fx_rate_updated_at TIMESTAMPTZ;
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/money"
+	"banana-auction/internal/domain/settlement"
+)
+
+type SettlementRepo struct {
+	db *sql.DB
+}
+
+func NewSettlementRepo(db *sql.DB) *SettlementRepo {
+	return &SettlementRepo{db: db}
+}
+
+func (r *SettlementRepo) Create(ctx context.Context, s settlement.Settlement) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var id int
+	fxBase, fxQuote, fxRate, fxUpdatedAt := fxSnapshot(s.FXRate)
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO settlements (auction_id, outcome, winner_id, winning_bid_id, currency, clearing_price_per_kg,
+				total_weight_kg, total_amount, commission_rate, commission,
+				fx_base, fx_quote, fx_rate, fx_rate_updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`,
+			s.AuctionID, s.Outcome, s.WinnerID, s.WinningBidID, s.Currency, s.ClearingPricePerKG.String(),
+			s.TotalWeightKG, s.TotalAmount.String(), s.CommissionRate.String(), s.Commission.String(),
+			fxBase, fxQuote, fxRate, fxUpdatedAt,
+		).Scan(&id)
+		if IsDuplicateKeyError(err) {
+			return settlement.ErrAlreadyExists
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, a := range s.Allocations {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO settlement_allocations (settlement_id, bid_id, buyer_id, bid_quantity_kg,
+					quantity_kg, price_per_kg, amount)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				id, a.BidID, a.BuyerID, a.BidQuantityKG, a.QuantityKG, a.PricePerKG.String(), a.Amount.String(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return id, err
+}
+
+func (r *SettlementRepo) GetByAuctionID(ctx context.Context, auctionID int) (settlement.Settlement, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var s settlement.Settlement
+	var winnerID, winningBidID sql.NullInt64
+	var fxBase, fxQuote, fxRate sql.NullString
+	var fxUpdatedAt sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, auction_id, outcome, winner_id, winning_bid_id, currency, clearing_price_per_kg,
+			total_weight_kg, total_amount, commission_rate, commission,
+			fx_base, fx_quote, fx_rate, fx_rate_updated_at, created_at
+		FROM settlements WHERE auction_id = $1`, auctionID,
+	).Scan(&s.ID, &s.AuctionID, &s.Outcome, &winnerID, &winningBidID, &s.Currency, amount{&s.ClearingPricePerKG, &s.Currency},
+		&s.TotalWeightKG, amount{&s.TotalAmount, &s.Currency}, rate{&s.CommissionRate}, amount{&s.Commission, &s.Currency},
+		&fxBase, &fxQuote, &fxRate, &fxUpdatedAt, &s.CreatedAt)
+	if err == sql.ErrNoRows {
+		return settlement.Settlement{}, settlement.ErrNotFound
+	}
+	if err != nil {
+		return settlement.Settlement{}, err
+	}
+	s.WinnerID = nullIntPtr(winnerID)
+	s.WinningBidID = nullIntPtr(winningBidID)
+	if fxBase.Valid {
+		parsed, err := money.ParseRate(fxRate.String)
+		if err != nil {
+			return settlement.Settlement{}, err
+		}
+		s.FXRate = &fx.Rate{
+			Base:      money.Currency(fxBase.String),
+			Quote:     money.Currency(fxQuote.String),
+			Rate:      parsed,
+			UpdatedAt: fxUpdatedAt.Time,
+		}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT bid_id, buyer_id, bid_quantity_kg, quantity_kg, price_per_kg, amount
+		FROM settlement_allocations WHERE settlement_id = $1 ORDER BY id`, s.ID)
+	if err != nil {
+		return settlement.Settlement{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a settlement.Allocation
+		err := rows.Scan(&a.BidID, &a.BuyerID, &a.BidQuantityKG, &a.QuantityKG,
+			amount{&a.PricePerKG, &s.Currency}, amount{&a.Amount, &s.Currency})
+		if err != nil {
+			return settlement.Settlement{}, err
+		}
+		a.PartialFill = a.QuantityKG < a.BidQuantityKG
+		s.Allocations = append(s.Allocations, a)
+	}
+	return s, rows.Err()
+}
+
+// fxSnapshot is a settlement's rate snapshot as its four nullable columns.
+func fxSnapshot(r *fx.Rate) (base, quote, rate, updatedAt any) {
+	if r == nil {
+		return nil, nil, nil, nil
+	}
+	return r.Base, r.Quote, r.Rate.String(), r.UpdatedAt
+}
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
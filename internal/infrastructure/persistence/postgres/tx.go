@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"time"
+
+	"banana-auction/internal/domain/transaction"
+)
+
+const (
+	// maxTxAttempts is how often TxManager.Do runs a unit of work that keeps
+	// failing to serialize before giving up.
+	maxTxAttempts = 5
+	txRetryBase   = 10 * time.Millisecond
+)
+
+type txKey struct{}
+
+// queryer is the part of *sql.DB and *sql.Tx the repositories need.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// conn returns the transaction ctx carries, or db outside a unit of work.
+func conn(ctx context.Context, db *sql.DB) queryer {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// inTx runs fn in the transaction ctx carries, or in a new one on db that is
+// committed when fn succeeds. Repositories whose statements must be atomic
+// on their own use it so they still join a caller's unit of work.
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// TxManager implements transaction.Manager on top of database/sql.
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+func (m *TxManager) Do(ctx context.Context, iso transaction.Isolation, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.run(ctx, iso, fn)
+		if err == nil || !IsSerializationError(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		// Back off with jitter so the transactions that collided don't
+		// collide again on the retry.
+		backoff := txRetryBase << (attempt - 1)
+		backoff += rand.N(backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (m *TxManager) run(ctx context.Context, iso transaction.Isolation, fn func(ctx context.Context) error) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: isolationLevel(iso)})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func isolationLevel(iso transaction.Isolation) sql.IsolationLevel {
+	switch iso {
+	case transaction.RepeatableRead:
+		return sql.LevelRepeatableRead
+	case transaction.Serializable:
+		return sql.LevelSerializable
+	default:
+		return sql.LevelReadCommitted
+	}
+}
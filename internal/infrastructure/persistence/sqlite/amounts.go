@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"banana-auction/internal/domain/money"
+)
+
+// Amounts are stored as INTEGER counts of their currency's minor unit
+// (cents for USD), and rates as INTEGER millionths, so SQLite never holds a
+// float. Every row that holds an amount keeps its currency in a column of
+// its own.
+
+// amount scans an INTEGER minor-unit column into dst in the currency *cur.
+// Rows.Scan fills destinations in order, so the currency column must come
+// earlier in the select list.
+type amount struct {
+	dst *money.Amount
+	cur *money.Currency
+}
+
+func (s amount) Scan(v any) error {
+	minor, ok := v.(int64)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into an amount", v)
+	}
+	*s.dst = money.New(minor, *s.cur)
+	return nil
+}
+
+// nullAmount is amount for nullable columns: NULL leaves *dst nil.
+type nullAmount struct {
+	dst **money.Amount
+	cur *money.Currency
+}
+
+func (s nullAmount) Scan(v any) error {
+	if v == nil {
+		*s.dst = nil
+		return nil
+	}
+	*s.dst = new(money.Amount)
+	return amount{dst: *s.dst, cur: s.cur}.Scan(v)
+}
+
+// rate scans an INTEGER millionths column into dst.
+type rate struct {
+	dst *money.Rate
+}
+
+func (s rate) Scan(v any) error {
+	n, ok := v.(int64)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into a rate", v)
+	}
+	*s.dst = money.NewRate(n)
+	return nil
+}
@@ -0,0 +1,310 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"banana-auction/internal/domain/auction"
+)
+
+const auctionColumns = `id, lot_id, start_date, duration_days, currency, initial_price_per_kg, auction_type, status, winning_bid_id,
+	soft_close_window_minutes, soft_close_extension_minutes, soft_close_max_extension_minutes, extension_minutes,
+	dutch_floor_price_per_kg, dutch_decrement_per_kg, dutch_decrement_interval_minutes, clearing,
+	reserve_price_per_kg, buy_now_price_per_kg, cancel_reason`
+
+// auctionStartsAtSQL and auctionEndsAtSQL compute an auction's schedule in
+// SQL, as Unix milliseconds, the same way auction.Auction's EndTime does.
+// They refer to the auctions table as a.
+const (
+	auctionStartsAtSQL = `CAST(ROUND(unixepoch(a.start_date, 'subsec') * 1000) AS INTEGER)`
+	auctionEndsAtSQL   = `(` + auctionStartsAtSQL + ` + (a.duration_days * 1440 + a.extension_minutes) * 60000)`
+	sealedTypesSQL     = `('sealed_first_price', 'sealed_second_price')`
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAuction(row rowScanner) (auction.Auction, error) {
+	var a auction.Auction
+	var winningBidID sql.NullInt64
+	if err := row.Scan(auctionDest(&a, &winningBidID)...); err != nil {
+		return auction.Auction{}, err
+	}
+	a.WinningBidID = nullIntPtr(winningBidID)
+	return a, nil
+}
+
+// auctionDest lists scan destinations for auctionColumns, so queries that
+// select extra columns after them can append their own.
+func auctionDest(a *auction.Auction, winningBidID *sql.NullInt64) []any {
+	cur := &a.Currency
+	return []any{&a.ID, &a.LotID, &a.StartDate, &a.DurationDays, cur, amount{&a.InitialPricePerKG, cur}, &a.Type, &a.Status, winningBidID,
+		&a.SoftClose.WindowMinutes, &a.SoftClose.ExtensionMinutes, &a.SoftClose.MaxExtensionMinutes, &a.ExtensionMinutes,
+		amount{&a.Dutch.FloorPricePerKG, cur}, amount{&a.Dutch.DecrementPerKG, cur}, &a.Dutch.DecrementIntervalMinutes, &a.Clearing,
+		amount{&a.ReservePricePerKG, cur}, amount{&a.BuyNowPricePerKG, cur}, &a.CancelReason}
+}
+
+type AuctionRepo struct {
+	db *sql.DB
+}
+
+func NewAuctionRepo(db *sql.DB) *AuctionRepo {
+	return &AuctionRepo{db: db}
+}
+
+func (r *AuctionRepo) Create(ctx context.Context, a auction.Auction) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO auctions (lot_id, start_date, duration_days, currency, initial_price_per_kg, auction_type, status,
+			soft_close_window_minutes, soft_close_extension_minutes, soft_close_max_extension_minutes,
+			dutch_floor_price_per_kg, dutch_decrement_per_kg, dutch_decrement_interval_minutes, clearing,
+			reserve_price_per_kg, buy_now_price_per_kg)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13, ?14, ?15, ?16) RETURNING id`,
+		a.LotID, a.StartDate.UTC(), a.DurationDays, a.Currency, a.InitialPricePerKG.Minor(), a.Type, a.Status,
+		a.SoftClose.WindowMinutes, a.SoftClose.ExtensionMinutes, a.SoftClose.MaxExtensionMinutes,
+		a.Dutch.FloorPricePerKG.Minor(), a.Dutch.DecrementPerKG.Minor(), a.Dutch.DecrementIntervalMinutes, a.Clearing,
+		a.ReservePricePerKG.Minor(), a.BuyNowPricePerKG.Minor(),
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *AuctionRepo) GetByID(ctx context.Context, id int) (auction.Auction, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	a, err := scanAuction(conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+auctionColumns+`
+		FROM auctions WHERE id = ?1`, id,
+	))
+	if err == sql.ErrNoRows {
+		return auction.Auction{}, auction.ErrNotFound
+	}
+	if err != nil {
+		return auction.Auction{}, err
+	}
+	return a, nil
+}
+
+func (r *AuctionRepo) Update(ctx context.Context, a auction.Auction) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	res, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE auctions SET start_date = ?1, duration_days = ?2, initial_price_per_kg = ?3,
+			reserve_price_per_kg = ?4, buy_now_price_per_kg = ?5, auction_type = ?6,
+			soft_close_window_minutes = ?7, soft_close_extension_minutes = ?8, soft_close_max_extension_minutes = ?9,
+			dutch_floor_price_per_kg = ?10, dutch_decrement_per_kg = ?11, dutch_decrement_interval_minutes = ?12,
+			clearing = ?13
+		WHERE id = ?14 AND status = ?15`,
+		a.StartDate.UTC(), a.DurationDays, a.InitialPricePerKG.Minor(),
+		a.ReservePricePerKG.Minor(), a.BuyNowPricePerKG.Minor(), a.Type,
+		a.SoftClose.WindowMinutes, a.SoftClose.ExtensionMinutes, a.SoftClose.MaxExtensionMinutes,
+		a.Dutch.FloorPricePerKG.Minor(), a.Dutch.DecrementPerKG.Minor(), a.Dutch.DecrementIntervalMinutes,
+		a.Clearing, a.ID, a.Status,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *AuctionRepo) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM auctions WHERE id = ?1`, id)
+	return err
+}
+
+func (r *AuctionRepo) List(ctx context.Context) ([]auction.Auction, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+auctionColumns+`
+		FROM auctions`)
+	if err != nil {
+		return nil, err
+	}
+	return scanAuctions(rows)
+}
+
+func (r *AuctionRepo) ListByStatus(ctx context.Context, statuses ...auction.Status) ([]auction.Auction, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	placeholders := make([]string, len(statuses))
+	values := make([]any, len(statuses))
+	for i, s := range statuses {
+		placeholders[i] = fmt.Sprintf("?%d", i+1)
+		values[i] = string(s)
+	}
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+auctionColumns+`
+		FROM auctions WHERE status IN (`+strings.Join(placeholders, ", ")+`)`, values...)
+	if err != nil {
+		return nil, err
+	}
+	return scanAuctions(rows)
+}
+
+func scanAuctions(rows *sql.Rows) ([]auction.Auction, error) {
+	defer rows.Close()
+
+	var auctions []auction.Auction
+	for rows.Next() {
+		a, err := scanAuction(rows)
+		if err != nil {
+			return nil, err
+		}
+		auctions = append(auctions, a)
+	}
+	return auctions, rows.Err()
+}
+
+func (r *AuctionRepo) ListBySeller(ctx context.Context, sellerID int) ([]auction.Summary, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+auctionColumns+`, cultivar, total_weight_kg, highest, bid_count, ends_at,
+			CASE WHEN status IN ('scheduled', 'live')
+				THEN CAST(ROUND(MAX(ends_at - `+nowMillisSQL+`, 0) / 1000.0) AS INTEGER)
+				ELSE 0
+			END
+		FROM (
+			SELECT a.*, l.cultivar, l.total_weight_kg, COALESCE(stats.bid_count, 0) AS bid_count,
+				CASE WHEN a.auction_type IN `+sealedTypesSQL+` AND a.status IN ('scheduled', 'live')
+					THEN NULL ELSE stats.highest
+				END AS highest,
+				`+auctionEndsAtSQL+` AS ends_at
+			FROM auctions a
+			JOIN lots l ON l.id = a.lot_id
+			LEFT JOIN (
+				SELECT auction_id, MAX(bid_price_per_kg) AS highest, COUNT(*) AS bid_count
+				FROM bids GROUP BY auction_id
+			) stats ON stats.auction_id = a.id
+			WHERE l.seller_id = ?1
+		) s
+		ORDER BY id DESC`, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []auction.Summary
+	for rows.Next() {
+		var s auction.Summary
+		var winningBidID sql.NullInt64
+		var endsAt int64
+		dest := append(auctionDest(&s.Auction, &winningBidID),
+			&s.Cultivar, &s.TotalWeightKG, nullAmount{&s.HighestBidPerKG, &s.Currency}, &s.BidCount, &endsAt, &s.TimeRemainingSeconds)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		s.WinningBidID = nullIntPtr(winningBidID)
+		s.EndsAt = millisTime(endsAt)
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+func (r *AuctionRepo) ExistsForLot(ctx context.Context, lotID int) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var count int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM auctions WHERE lot_id = ?1`, lotID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *AuctionRepo) Transition(ctx context.Context, id int, from, to auction.Status) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	res, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE auctions SET status = ?1
+		WHERE id = ?2 AND status = ?3`,
+		to, id, from,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *AuctionRepo) Cancel(ctx context.Context, id int, reason string) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	res, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE auctions SET status = ?1, cancel_reason = ?2, cancelled_at = `+nowSQL+`
+		WHERE id = ?3 AND status IN (?4, ?5)`,
+		auction.StatusCancelled, reason, id, auction.StatusScheduled, auction.StatusLive,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *AuctionRepo) Close(ctx context.Context, id int) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	closed := false
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		// The transaction holds the write lock, like bid placement, so an
+		// in-flight bid either commits before we pick the winner or is
+		// rejected afterwards.
+		a, err := scanAuction(tx.QueryRowContext(ctx, `
+			SELECT `+auctionColumns+`
+			FROM auctions WHERE id = ?1`, id,
+		))
+		if err == sql.ErrNoRows {
+			return auction.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if a.Status != auction.StatusLive {
+			return nil
+		}
+		// A soft-close extension may have moved the end since the caller looked.
+		if time.Now().Before(a.EndTime()) {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE auctions SET status = ?1, closed_at = `+nowSQL+`, winning_bid_id = (
+				SELECT id FROM bids WHERE auction_id = ?2
+				ORDER BY bid_price_per_kg DESC, id ASC LIMIT 1
+			)
+			WHERE id = ?2`,
+			auction.StatusClosed, id,
+		)
+		if err != nil {
+			return err
+		}
+		closed = true
+		return nil
+	})
+	return closed, err
+}
@@ -0,0 +1,131 @@
+// Package sqlite implements the repositories on an embedded SQLite database
+// for deployments without a Postgres server.
+//
+// The schema and queries follow the postgres package, adapted where the
+// dialects differ:
+//   - SERIAL columns are INTEGER PRIMARY KEY AUTOINCREMENT, so like a
+//     sequence an id is never handed out twice, even after deletes.
+//   - The bundled SQLite is new enough for RETURNING and ON CONFLICT, so
+//     inserts still read their id back in the same statement.
+//   - Timestamps computed in SQL are Unix milliseconds (see millisTime);
+//     stored ones are UTC text the driver parses back into time.Time, as it
+//     does YYYY-MM-DD text in DATE columns.
+//   - There are no row locks. Every transaction begins IMMEDIATE, taking the
+//     database's single write lock up front, so WithAuctionLock serializes
+//     bidders (on all auctions rather than one) and a transaction never fails
+//     halfway through when it first writes.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"banana-auction/config"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// nowSQL is the current time in the format stored TIMESTAMP columns use,
+// and nowMillisSQL the current time as Unix milliseconds.
+const (
+	nowSQL       = `strftime('%Y-%m-%d %H:%M:%f', 'now')`
+	nowMillisSQL = `CAST(ROUND(unixepoch('now', 'subsec') * 1000) AS INTEGER)`
+)
+
+var (
+	db *sql.DB
+
+	// queryTimeout bounds every repository call; zero means no limit
+	// beyond the caller's own context.
+	queryTimeout time.Duration
+)
+
+// Connect opens the database file at cfg.SqlitePath, creating it if needed,
+// without touching the schema.
+func Connect(cfg *config.Config) error {
+	// Connections wait for the write lock for up to the DB timeout before
+	// giving up with SQLITE_BUSY.
+	busyTimeout := cfg.DBTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = 5 * time.Second
+	}
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "sqlite")
+
+	var err error
+	db, err = sql.Open("sqlite", "file:"+cfg.SqlitePath+"?"+params.Encode())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	queryTimeout = cfg.DBTimeout
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return nil
+}
+
+// InitDB connects and applies any pending migrations.
+func InitDB(cfg *config.Config) error {
+	if err := Connect(cfg); err != nil {
+		return err
+	}
+
+	applied, err := MigrateUp(db)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if applied > 0 {
+		log.Printf("Applied %d database migrations", applied)
+	}
+
+	return nil
+}
+
+// withTimeout derives the context for one repository call from the caller's,
+// so a cancelled request or a stuck query never holds a connection for
+// longer than the configured DB timeout.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+func GetDB() *sql.DB {
+	return db
+}
+
+func IsDuplicateKeyError(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+}
+
+// IsBusyError reports whether SQLite gave up waiting for a lock another
+// connection held, in which case running the transaction again can succeed.
+func IsBusyError(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code() & 0xff // primary result code
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
+
+// millisTime converts a Unix millisecond timestamp computed in SQL.
+func millisTime(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"banana-auction/internal/domain/fx"
+	"banana-auction/internal/domain/money"
+)
+
+type FXRepo struct {
+	db *sql.DB
+}
+
+func NewFXRepo(db *sql.DB) *FXRepo {
+	return &FXRepo{db: db}
+}
+
+const fxColumns = `base, quote, rate, updated_at`
+
+func scanFXRate(row rowScanner) (fx.Rate, error) {
+	var r fx.Rate
+	err := row.Scan(&r.Base, &r.Quote, rate{&r.Rate}, &r.UpdatedAt)
+	return r, err
+}
+
+func (r *FXRepo) List(ctx context.Context) ([]fx.Rate, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+fxColumns+` FROM fx_rates ORDER BY base, quote`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rates []fx.Rate
+	for rows.Next() {
+		rate, err := scanFXRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *FXRepo) Get(ctx context.Context, base, quote money.Currency) (fx.Rate, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rate, err := scanFXRate(conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+fxColumns+` FROM fx_rates WHERE base = ?1 AND quote = ?2`, base, quote))
+	if err == sql.ErrNoRows {
+		return fx.Rate{}, fx.ErrNotFound
+	}
+	return rate, err
+}
+
+func (r *FXRepo) Save(ctx context.Context, rates []fx.Rate) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, rate := range rates {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO fx_rates (base, quote, rate)
+				VALUES (?1, ?2, ?3)
+				ON CONFLICT (base, quote) DO UPDATE
+				SET rate = EXCLUDED.rate, updated_at = `+nowSQL,
+				rate.Base, rate.Quote, rate.Rate.Millionths(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *FXRepo) Delete(ctx context.Context, base, quote money.Currency) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	res, err := conn(ctx, r.db).ExecContext(ctx, `
+		DELETE FROM fx_rates WHERE base = ?1 AND quote = ?2`, base, quote)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fx.ErrNotFound
+	}
+	return nil
+}
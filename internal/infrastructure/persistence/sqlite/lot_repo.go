@@ -0,0 +1,195 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"banana-auction/internal/domain/lot"
+	"banana-auction/internal/domain/money"
+)
+
+type LotRepo struct {
+	db *sql.DB
+}
+
+func NewLotRepo(db *sql.DB) *LotRepo {
+	return &LotRepo{db: db}
+}
+
+func (r *LotRepo) Create(ctx context.Context, l lot.Lot) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO lots (seller_id, cultivar, planted_country, harvest_date, total_weight_kg)
+		VALUES (?1, ?2, ?3, ?4, ?5) RETURNING id`,
+		l.SellerID, l.Cultivar, l.PlantedCountry, l.HarvestDate.Format(time.DateOnly), l.TotalWeightKG,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *LotRepo) GetByID(ctx context.Context, id int) (lot.Lot, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var l lot.Lot
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, seller_id, cultivar, planted_country, harvest_date, total_weight_kg
+		FROM lots WHERE id = ?1`, id,
+	).Scan(&l.ID, &l.SellerID, &l.Cultivar, &l.PlantedCountry, &l.HarvestDate, &l.TotalWeightKG)
+	if err == sql.ErrNoRows {
+		return lot.Lot{}, lot.ErrNotFound
+	}
+	if err != nil {
+		return lot.Lot{}, err
+	}
+	return l, nil
+}
+
+func (r *LotRepo) Update(ctx context.Context, l lot.Lot) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE lots SET harvest_date = ?1
+		WHERE id = ?2 AND seller_id = ?3`,
+		l.HarvestDate.Format(time.DateOnly), l.ID, l.SellerID,
+	)
+	return err
+}
+
+func (r *LotRepo) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkLotAuctions(ctx, tx, id); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `UPDATE auctions SET winning_bid_id = NULL WHERE lot_id = ?1`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM proxy_bids WHERE auction_id IN (SELECT id FROM auctions WHERE lot_id = ?1)`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM bids WHERE auction_id IN (SELECT id FROM auctions WHERE lot_id = ?1)`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM auctions WHERE lot_id = ?1`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM lots WHERE id = ?1`, id)
+		return err
+	})
+}
+
+func (r *LotRepo) List(ctx context.Context) ([]lot.Lot, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, seller_id, cultivar, planted_country, harvest_date, total_weight_kg
+		FROM lots`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []lot.Lot
+	for rows.Next() {
+		var l lot.Lot
+		if err := rows.Scan(&l.ID, &l.SellerID, &l.Cultivar, &l.PlantedCountry, &l.HarvestDate, &l.TotalWeightKG); err != nil {
+			return nil, err
+		}
+		lots = append(lots, l)
+	}
+	return lots, nil
+}
+func (r *LotRepo) ListBySeller(ctx context.Context, sellerID int) ([]lot.Summary, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, seller_id, cultivar, planted_country, harvest_date, total_weight_kg,
+			auction_id, auction_status, currency, highest, bid_count, ends_at,
+			CASE WHEN auction_status IN ('scheduled', 'live')
+				THEN CAST(ROUND(MAX(ends_at - `+nowMillisSQL+`, 0) / 1000.0) AS INTEGER)
+				ELSE 0
+			END
+		FROM (
+			SELECT l.*, a.id AS auction_id, a.status AS auction_status, COALESCE(a.currency, '') AS currency,
+				COALESCE(stats.bid_count, 0) AS bid_count,
+				CASE WHEN a.auction_type IN `+sealedTypesSQL+` AND a.status IN ('scheduled', 'live')
+					THEN NULL ELSE stats.highest
+				END AS highest,
+				`+auctionEndsAtSQL+` AS ends_at
+			FROM lots l
+			LEFT JOIN auctions a ON a.lot_id = l.id
+			LEFT JOIN (
+				SELECT auction_id, MAX(bid_price_per_kg) AS highest, COUNT(*) AS bid_count
+				FROM bids GROUP BY auction_id
+			) stats ON stats.auction_id = a.id
+			WHERE l.seller_id = ?1
+		) s
+		ORDER BY id DESC`, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []lot.Summary
+	for rows.Next() {
+		var s lot.Summary
+		var auctionID sql.NullInt64
+		var status sql.NullString
+		var currency money.Currency
+		var endsAt sql.NullInt64
+		err := rows.Scan(&s.ID, &s.SellerID, &s.Cultivar, &s.PlantedCountry, &s.HarvestDate, &s.TotalWeightKG,
+			&auctionID, &status, &currency, nullAmount{&s.HighestBidPerKG, &currency}, &s.BidCount, &endsAt, &s.TimeRemainingSeconds)
+		if err != nil {
+			return nil, err
+		}
+		s.AuctionID = nullIntPtr(auctionID)
+		s.AuctionStatus = status.String
+		if endsAt.Valid {
+			t := millisTime(endsAt.Int64)
+			s.EndsAt = &t
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+// checkLotAuctions returns the error that stops lot id from being deleted.
+// Only a lot whose auctions were all cancelled, or that has none, may go.
+func checkLotAuctions(ctx context.Context, tx *sql.Tx, id int) error {
+	var settled, started, scheduled int
+	err := tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM settlements s JOIN auctions a ON a.id = s.auction_id WHERE a.lot_id = ?1),
+			COUNT(*) FILTER (WHERE status NOT IN ('scheduled', 'cancelled')),
+			COUNT(*) FILTER (WHERE status = 'scheduled')
+		FROM auctions WHERE lot_id = ?1`, id,
+	).Scan(&settled, &started, &scheduled)
+	switch {
+	case err != nil:
+		return err
+	case settled > 0:
+		return lot.ErrSettled
+	case started > 0:
+		return lot.ErrAuctionStarted
+	case scheduled > 0:
+		return lot.ErrHasAuction
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// loadMigrations reads the embedded NNNN_name.up.sql / NNNN_name.down.sql
+// pairs, sorted by version.
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		file := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(file, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(file, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("unexpected migration file %s", file)
+		}
+
+		base := strings.TrimSuffix(file, "."+direction+".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration file %s must be named NNNN_name.%s.sql", file, direction)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("migration file %s has an invalid version", file)
+		}
+
+		body, err := migrationFiles.ReadFile(path.Join("migrations", file))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %d has mismatched names %s and %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d is missing its up or down step", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func ensureMigrationsTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func appliedVersions(q queryer) (map[int]time.Time, error) {
+	rows, err := q.QueryContext(context.Background(), `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// MigrateUp applies every pending migration in order and returns how many
+// were applied. Each migration runs in its own transaction.
+func MigrateUp(db *sql.DB) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range migrations {
+		ran, err := runMigration(db, m.Version, false, m.Up, func(tx *sql.Tx) error {
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES (?1, ?2)`, m.Version, m.Name)
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("migration %04d_%s up: %w", m.Version, m.Name, err)
+		}
+		if ran {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MigrateDown rolls back the latest steps applied migrations and returns how
+// many were rolled back.
+func MigrateDown(db *sql.DB, steps int) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		m := migrations[i]
+		ran, err := runMigration(db, m.Version, true, m.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?1`, m.Version)
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("migration %04d_%s down: %w", m.Version, m.Name, err)
+		}
+		if ran {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MigrationStatuses lists every known migration and when it was applied.
+func MigrationStatuses(db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []MigrationStatus
+	for _, m := range migrations {
+		s := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			s.AppliedAt = &at
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// runMigration runs script if version's applied state is still wantApplied,
+// and reports whether it did. SQLite has no advisory locks, but the
+// transaction holds the database write lock from its first statement, so
+// checking schema_migrations inside it keeps two processes from running the
+// same migration.
+func runMigration(db *sql.DB, version int, wantApplied bool, script string, record func(tx *sql.Tx) error) (bool, error) {
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	applied, err := appliedVersions(tx)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := applied[version]; ok != wantApplied {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		return false, err
+	}
+	if err := record(tx); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
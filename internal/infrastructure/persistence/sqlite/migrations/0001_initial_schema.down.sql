@@ -0,0 +1,12 @@
+-- auctions and bids reference each other, so check foreign keys only once
+-- everything is gone.
+PRAGMA defer_foreign_keys = ON;
+
+DROP TABLE proxy_bids;
+DROP TABLE refresh_tokens;
+DROP TABLE settlement_allocations;
+DROP TABLE settlements;
+DROP TABLE bids;
+DROP TABLE auctions;
+DROP TABLE lots;
+DROP TABLE users;
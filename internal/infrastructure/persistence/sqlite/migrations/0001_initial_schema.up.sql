@@ -0,0 +1,109 @@
+-- The schema of postgres migrations 0001 to 0014 in one step. SERIAL becomes
+-- INTEGER PRIMARY KEY AUTOINCREMENT, TIMESTAMPTZ becomes TIMESTAMP text in
+-- UTC and FLOAT becomes REAL.
+CREATE TABLE users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	username TEXT UNIQUE NOT NULL,
+	password_hash TEXT NOT NULL,
+	name TEXT NOT NULL,
+	role TEXT NOT NULL CHECK (role IN ('seller', 'buyer'))
+);
+
+CREATE TABLE lots (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	seller_id INTEGER REFERENCES users(id),
+	cultivar TEXT NOT NULL,
+	planted_country TEXT NOT NULL,
+	harvest_date TEXT NOT NULL,
+	total_weight_kg INTEGER NOT NULL
+);
+
+CREATE TABLE auctions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	lot_id INTEGER REFERENCES lots(id),
+	start_date TEXT NOT NULL,
+	duration_days INTEGER NOT NULL,
+	initial_price_per_kg REAL NOT NULL,
+	status TEXT NOT NULL DEFAULT 'scheduled'
+		CHECK (status IN ('scheduled', 'live', 'closed', 'cancelled', 'settled', 'unsold')),
+	winning_bid_id INTEGER REFERENCES bids(id),
+	closed_at TIMESTAMP,
+	soft_close_window_minutes INTEGER NOT NULL DEFAULT 0,
+	soft_close_extension_minutes INTEGER NOT NULL DEFAULT 0,
+	soft_close_max_extension_minutes INTEGER NOT NULL DEFAULT 0,
+	extension_minutes INTEGER NOT NULL DEFAULT 0,
+	auction_type TEXT NOT NULL DEFAULT 'english'
+		CHECK (auction_type IN ('english', 'sealed_first_price', 'sealed_second_price', 'dutch', 'multi_unit')),
+	dutch_floor_price_per_kg REAL NOT NULL DEFAULT 0,
+	dutch_decrement_per_kg REAL NOT NULL DEFAULT 0,
+	dutch_decrement_interval_minutes INTEGER NOT NULL DEFAULT 0,
+	clearing TEXT NOT NULL DEFAULT '' CHECK (clearing IN ('', 'uniform', 'pay_as_bid')),
+	reserve_price_per_kg REAL NOT NULL DEFAULT 0,
+	buy_now_price_per_kg REAL NOT NULL DEFAULT 0,
+	cancel_reason TEXT NOT NULL DEFAULT '',
+	cancelled_at TIMESTAMP
+);
+
+CREATE TABLE bids (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	auction_id INTEGER REFERENCES auctions(id),
+	buyer_id INTEGER REFERENCES users(id),
+	bid_price_per_kg REAL NOT NULL,
+	is_proxy BOOLEAN NOT NULL DEFAULT FALSE,
+	quantity_kg INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE settlements (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	auction_id INTEGER UNIQUE NOT NULL REFERENCES auctions(id),
+	outcome TEXT NOT NULL CHECK (outcome IN ('sold', 'unsold')),
+	winner_id INTEGER REFERENCES users(id),
+	winning_bid_id INTEGER REFERENCES bids(id),
+	clearing_price_per_kg REAL NOT NULL,
+	total_weight_kg INTEGER NOT NULL,
+	total_amount REAL NOT NULL,
+	commission_rate REAL NOT NULL,
+	commission REAL NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE TABLE settlement_allocations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	settlement_id INTEGER NOT NULL REFERENCES settlements(id) ON DELETE CASCADE,
+	bid_id INTEGER NOT NULL REFERENCES bids(id),
+	buyer_id INTEGER NOT NULL REFERENCES users(id),
+	bid_quantity_kg INTEGER NOT NULL,
+	quantity_kg INTEGER NOT NULL,
+	price_per_kg REAL NOT NULL,
+	amount REAL NOT NULL
+);
+
+CREATE TABLE refresh_tokens (
+	id TEXT PRIMARY KEY,
+	family_id TEXT NOT NULL,
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	expires_at TIMESTAMP NOT NULL,
+	used_at TIMESTAMP,
+	revoked_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE TABLE proxy_bids (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	auction_id INTEGER NOT NULL REFERENCES auctions(id),
+	buyer_id INTEGER NOT NULL REFERENCES users(id),
+	max_price_per_kg REAL NOT NULL,
+	registered_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+	UNIQUE (auction_id, buyer_id)
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+CREATE INDEX lots_seller_id_idx ON lots (seller_id);
+CREATE INDEX auctions_lot_id_idx ON auctions (lot_id);
+CREATE INDEX auctions_status_idx ON auctions (status);
+CREATE INDEX bids_auction_id_price_idx ON bids (auction_id, bid_price_per_kg DESC);
+CREATE INDEX bids_buyer_id_auction_id_idx ON bids (buyer_id, auction_id);
+CREATE INDEX settlement_allocations_settlement_id_idx ON settlement_allocations (settlement_id);
+CREATE INDEX lots_cultivar_idx ON lots (lower(cultivar));
+CREATE INDEX lots_planted_country_idx ON lots (lower(planted_country));
+CREATE INDEX lots_harvest_date_idx ON lots (harvest_date);
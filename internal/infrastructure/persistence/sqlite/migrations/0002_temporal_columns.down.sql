@@ -0,0 +1,12 @@
+DROP INDEX auctions_start_date_idx;
+ALTER TABLE auctions ADD COLUMN start_text TEXT;
+UPDATE auctions SET start_text = strftime('%Y-%m-%dT%H:%M:%SZ', start_date);
+ALTER TABLE auctions DROP COLUMN start_date;
+ALTER TABLE auctions RENAME COLUMN start_text TO start_date;
+
+DROP INDEX lots_harvest_date_idx;
+ALTER TABLE lots ADD COLUMN harvest_text TEXT;
+UPDATE lots SET harvest_text = harvest_date;
+ALTER TABLE lots DROP COLUMN harvest_date;
+ALTER TABLE lots RENAME COLUMN harvest_text TO harvest_date;
+CREATE INDEX lots_harvest_date_idx ON lots (harvest_date);
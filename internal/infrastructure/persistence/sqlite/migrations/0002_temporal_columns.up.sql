@@ -0,0 +1,48 @@
+-- Postgres migration 0015. Harvest and start dates move from free-form TEXT
+-- to DATE and TIMESTAMP columns, which the driver reads back as time.Time.
+-- Values are read the way the API used to read them: a harvest date is its
+-- leading YYYY-MM-DD, a start is RFC 3339 or a bare date taken as midnight
+-- UTC. If any row holds something else the migration stops before changing
+-- anything and its error lists those rows.
+CREATE TEMP TABLE date_problems (report TEXT NOT NULL);
+CREATE TEMP TRIGGER date_problems_abort BEFORE INSERT ON date_problems
+BEGIN
+	SELECT RAISE(ABORT, 'cannot convert these dates, fix them and migrate again: ' || NEW.report);
+END;
+
+INSERT INTO date_problems (report)
+SELECT group_concat(problem, '; ') FROM (
+	SELECT 1 AS kind, id, 'lot ' || id || ': harvest_date ' || quote(harvest_date) AS problem
+	FROM lots
+	WHERE NOT (harvest_date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'
+			OR harvest_date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]T[0-9][0-9]:[0-9][0-9]:[0-9][0-9]*')
+		OR date(substr(harvest_date, 1, 10)) IS NOT substr(harvest_date, 1, 10)
+	UNION ALL
+	SELECT 2, id, 'auction ' || id || ': start_date ' || quote(start_date)
+	FROM auctions
+	WHERE NOT (start_date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'
+			OR start_date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]T[0-9][0-9]:[0-9][0-9]:[0-9][0-9]*')
+		OR date(substr(start_date, 1, 10)) IS NOT substr(start_date, 1, 10)
+		OR unixepoch(start_date) IS NULL
+	ORDER BY 1, 2
+)
+HAVING count(*) > 0;
+
+DROP TRIGGER date_problems_abort;
+DROP TABLE date_problems;
+
+-- SQLite can't change a column's type, so each gets a new column. It can't
+-- add one NOT NULL without a default either; the repositories always write
+-- both dates.
+DROP INDEX lots_harvest_date_idx;
+ALTER TABLE lots ADD COLUMN harvest_day DATE;
+UPDATE lots SET harvest_day = substr(harvest_date, 1, 10);
+ALTER TABLE lots DROP COLUMN harvest_date;
+ALTER TABLE lots RENAME COLUMN harvest_day TO harvest_date;
+CREATE INDEX lots_harvest_date_idx ON lots (harvest_date);
+
+ALTER TABLE auctions ADD COLUMN starts_at TIMESTAMP;
+UPDATE auctions SET starts_at = strftime('%Y-%m-%d %H:%M:%f', start_date) || '+00:00';
+ALTER TABLE auctions DROP COLUMN start_date;
+ALTER TABLE auctions RENAME COLUMN starts_at TO start_date;
+CREATE INDEX auctions_start_date_idx ON auctions (start_date);
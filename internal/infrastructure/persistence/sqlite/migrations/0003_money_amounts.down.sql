@@ -0,0 +1,63 @@
+ALTER TABLE auctions ADD COLUMN initial_price_per_kg_real REAL NOT NULL DEFAULT 0;
+UPDATE auctions SET initial_price_per_kg_real = initial_price_per_kg / 100.0;
+ALTER TABLE auctions DROP COLUMN initial_price_per_kg;
+ALTER TABLE auctions RENAME COLUMN initial_price_per_kg_real TO initial_price_per_kg;
+ALTER TABLE auctions ADD COLUMN dutch_floor_price_per_kg_real REAL NOT NULL DEFAULT 0;
+UPDATE auctions SET dutch_floor_price_per_kg_real = dutch_floor_price_per_kg / 100.0;
+ALTER TABLE auctions DROP COLUMN dutch_floor_price_per_kg;
+ALTER TABLE auctions RENAME COLUMN dutch_floor_price_per_kg_real TO dutch_floor_price_per_kg;
+ALTER TABLE auctions ADD COLUMN dutch_decrement_per_kg_real REAL NOT NULL DEFAULT 0;
+UPDATE auctions SET dutch_decrement_per_kg_real = dutch_decrement_per_kg / 100.0;
+ALTER TABLE auctions DROP COLUMN dutch_decrement_per_kg;
+ALTER TABLE auctions RENAME COLUMN dutch_decrement_per_kg_real TO dutch_decrement_per_kg;
+ALTER TABLE auctions ADD COLUMN reserve_price_per_kg_real REAL NOT NULL DEFAULT 0;
+UPDATE auctions SET reserve_price_per_kg_real = reserve_price_per_kg / 100.0;
+ALTER TABLE auctions DROP COLUMN reserve_price_per_kg;
+ALTER TABLE auctions RENAME COLUMN reserve_price_per_kg_real TO reserve_price_per_kg;
+ALTER TABLE auctions ADD COLUMN buy_now_price_per_kg_real REAL NOT NULL DEFAULT 0;
+UPDATE auctions SET buy_now_price_per_kg_real = buy_now_price_per_kg / 100.0;
+ALTER TABLE auctions DROP COLUMN buy_now_price_per_kg;
+ALTER TABLE auctions RENAME COLUMN buy_now_price_per_kg_real TO buy_now_price_per_kg;
+
+DROP INDEX bids_auction_id_price_idx;
+ALTER TABLE bids ADD COLUMN bid_price_per_kg_real REAL NOT NULL DEFAULT 0;
+UPDATE bids SET bid_price_per_kg_real = bid_price_per_kg / 100.0;
+ALTER TABLE bids DROP COLUMN bid_price_per_kg;
+ALTER TABLE bids RENAME COLUMN bid_price_per_kg_real TO bid_price_per_kg;
+CREATE INDEX bids_auction_id_price_idx ON bids (auction_id, bid_price_per_kg DESC);
+
+ALTER TABLE proxy_bids ADD COLUMN max_price_per_kg_real REAL NOT NULL DEFAULT 0;
+UPDATE proxy_bids SET max_price_per_kg_real = max_price_per_kg / 100.0;
+ALTER TABLE proxy_bids DROP COLUMN max_price_per_kg;
+ALTER TABLE proxy_bids RENAME COLUMN max_price_per_kg_real TO max_price_per_kg;
+
+ALTER TABLE settlements ADD COLUMN clearing_price_per_kg_real REAL NOT NULL DEFAULT 0;
+UPDATE settlements SET clearing_price_per_kg_real = clearing_price_per_kg / 100.0;
+ALTER TABLE settlements DROP COLUMN clearing_price_per_kg;
+ALTER TABLE settlements RENAME COLUMN clearing_price_per_kg_real TO clearing_price_per_kg;
+ALTER TABLE settlements ADD COLUMN total_amount_real REAL NOT NULL DEFAULT 0;
+UPDATE settlements SET total_amount_real = total_amount / 100.0;
+ALTER TABLE settlements DROP COLUMN total_amount;
+ALTER TABLE settlements RENAME COLUMN total_amount_real TO total_amount;
+ALTER TABLE settlements ADD COLUMN commission_rate_real REAL NOT NULL DEFAULT 0;
+UPDATE settlements SET commission_rate_real = commission_rate / 1000000.0;
+ALTER TABLE settlements DROP COLUMN commission_rate;
+ALTER TABLE settlements RENAME COLUMN commission_rate_real TO commission_rate;
+ALTER TABLE settlements ADD COLUMN commission_real REAL NOT NULL DEFAULT 0;
+UPDATE settlements SET commission_real = commission / 100.0;
+ALTER TABLE settlements DROP COLUMN commission;
+ALTER TABLE settlements RENAME COLUMN commission_real TO commission;
+
+ALTER TABLE settlement_allocations ADD COLUMN price_per_kg_real REAL NOT NULL DEFAULT 0;
+UPDATE settlement_allocations SET price_per_kg_real = price_per_kg / 100.0;
+ALTER TABLE settlement_allocations DROP COLUMN price_per_kg;
+ALTER TABLE settlement_allocations RENAME COLUMN price_per_kg_real TO price_per_kg;
+ALTER TABLE settlement_allocations ADD COLUMN amount_real REAL NOT NULL DEFAULT 0;
+UPDATE settlement_allocations SET amount_real = amount / 100.0;
+ALTER TABLE settlement_allocations DROP COLUMN amount;
+ALTER TABLE settlement_allocations RENAME COLUMN amount_real TO amount;
+
+ALTER TABLE settlements DROP COLUMN currency;
+ALTER TABLE proxy_bids DROP COLUMN currency;
+ALTER TABLE bids DROP COLUMN currency;
+ALTER TABLE auctions DROP COLUMN currency;
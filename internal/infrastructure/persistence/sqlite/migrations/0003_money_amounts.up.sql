@@ -0,0 +1,70 @@
+-- Postgres migration 0016. Prices and amounts move from REAL to INTEGER
+-- minor units of their currency (cents), and commission rates to INTEGER
+-- millionths, so they compare and add exactly. Every table holding an amount
+-- gets the currency it is in. Rows written before currencies existed are
+-- taken as USD and rounded to the cent.
+ALTER TABLE auctions ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+ALTER TABLE bids ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+ALTER TABLE proxy_bids ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+ALTER TABLE settlements ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+
+-- As in 0002, each converted column is added, filled and renamed over the
+-- old one.
+ALTER TABLE auctions ADD COLUMN initial_price_per_kg_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE auctions SET initial_price_per_kg_minor = CAST(ROUND(initial_price_per_kg * 100) AS INTEGER);
+ALTER TABLE auctions DROP COLUMN initial_price_per_kg;
+ALTER TABLE auctions RENAME COLUMN initial_price_per_kg_minor TO initial_price_per_kg;
+ALTER TABLE auctions ADD COLUMN dutch_floor_price_per_kg_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE auctions SET dutch_floor_price_per_kg_minor = CAST(ROUND(dutch_floor_price_per_kg * 100) AS INTEGER);
+ALTER TABLE auctions DROP COLUMN dutch_floor_price_per_kg;
+ALTER TABLE auctions RENAME COLUMN dutch_floor_price_per_kg_minor TO dutch_floor_price_per_kg;
+ALTER TABLE auctions ADD COLUMN dutch_decrement_per_kg_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE auctions SET dutch_decrement_per_kg_minor = CAST(ROUND(dutch_decrement_per_kg * 100) AS INTEGER);
+ALTER TABLE auctions DROP COLUMN dutch_decrement_per_kg;
+ALTER TABLE auctions RENAME COLUMN dutch_decrement_per_kg_minor TO dutch_decrement_per_kg;
+ALTER TABLE auctions ADD COLUMN reserve_price_per_kg_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE auctions SET reserve_price_per_kg_minor = CAST(ROUND(reserve_price_per_kg * 100) AS INTEGER);
+ALTER TABLE auctions DROP COLUMN reserve_price_per_kg;
+ALTER TABLE auctions RENAME COLUMN reserve_price_per_kg_minor TO reserve_price_per_kg;
+ALTER TABLE auctions ADD COLUMN buy_now_price_per_kg_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE auctions SET buy_now_price_per_kg_minor = CAST(ROUND(buy_now_price_per_kg * 100) AS INTEGER);
+ALTER TABLE auctions DROP COLUMN buy_now_price_per_kg;
+ALTER TABLE auctions RENAME COLUMN buy_now_price_per_kg_minor TO buy_now_price_per_kg;
+
+DROP INDEX bids_auction_id_price_idx;
+ALTER TABLE bids ADD COLUMN bid_price_per_kg_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE bids SET bid_price_per_kg_minor = CAST(ROUND(bid_price_per_kg * 100) AS INTEGER);
+ALTER TABLE bids DROP COLUMN bid_price_per_kg;
+ALTER TABLE bids RENAME COLUMN bid_price_per_kg_minor TO bid_price_per_kg;
+CREATE INDEX bids_auction_id_price_idx ON bids (auction_id, bid_price_per_kg DESC);
+
+ALTER TABLE proxy_bids ADD COLUMN max_price_per_kg_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE proxy_bids SET max_price_per_kg_minor = CAST(ROUND(max_price_per_kg * 100) AS INTEGER);
+ALTER TABLE proxy_bids DROP COLUMN max_price_per_kg;
+ALTER TABLE proxy_bids RENAME COLUMN max_price_per_kg_minor TO max_price_per_kg;
+
+ALTER TABLE settlements ADD COLUMN clearing_price_per_kg_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE settlements SET clearing_price_per_kg_minor = CAST(ROUND(clearing_price_per_kg * 100) AS INTEGER);
+ALTER TABLE settlements DROP COLUMN clearing_price_per_kg;
+ALTER TABLE settlements RENAME COLUMN clearing_price_per_kg_minor TO clearing_price_per_kg;
+ALTER TABLE settlements ADD COLUMN total_amount_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE settlements SET total_amount_minor = CAST(ROUND(total_amount * 100) AS INTEGER);
+ALTER TABLE settlements DROP COLUMN total_amount;
+ALTER TABLE settlements RENAME COLUMN total_amount_minor TO total_amount;
+ALTER TABLE settlements ADD COLUMN commission_rate_millionths INTEGER NOT NULL DEFAULT 0;
+UPDATE settlements SET commission_rate_millionths = CAST(ROUND(commission_rate * 1000000) AS INTEGER);
+ALTER TABLE settlements DROP COLUMN commission_rate;
+ALTER TABLE settlements RENAME COLUMN commission_rate_millionths TO commission_rate;
+ALTER TABLE settlements ADD COLUMN commission_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE settlements SET commission_minor = CAST(ROUND(commission * 100) AS INTEGER);
+ALTER TABLE settlements DROP COLUMN commission;
+ALTER TABLE settlements RENAME COLUMN commission_minor TO commission;
+
+ALTER TABLE settlement_allocations ADD COLUMN price_per_kg_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE settlement_allocations SET price_per_kg_minor = CAST(ROUND(price_per_kg * 100) AS INTEGER);
+ALTER TABLE settlement_allocations DROP COLUMN price_per_kg;
+ALTER TABLE settlement_allocations RENAME COLUMN price_per_kg_minor TO price_per_kg;
+ALTER TABLE settlement_allocations ADD COLUMN amount_minor INTEGER NOT NULL DEFAULT 0;
+UPDATE settlement_allocations SET amount_minor = CAST(ROUND(amount * 100) AS INTEGER);
+ALTER TABLE settlement_allocations DROP COLUMN amount;
+ALTER TABLE settlement_allocations RENAME COLUMN amount_minor TO amount;
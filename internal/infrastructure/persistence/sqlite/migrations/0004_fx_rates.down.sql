@@ -0,0 +1,6 @@
+ALTER TABLE settlements DROP COLUMN fx_rate_updated_at;
+ALTER TABLE settlements DROP COLUMN fx_rate;
+ALTER TABLE settlements DROP COLUMN fx_quote;
+ALTER TABLE settlements DROP COLUMN fx_base;
+
+DROP TABLE fx_rates;
@@ -0,0 +1,15 @@
+-- Postgres migration 0017. Rates are INTEGER millionths like commission
+-- rates.
+CREATE TABLE fx_rates (
+	base TEXT NOT NULL,
+	quote TEXT NOT NULL,
+	rate INTEGER NOT NULL CHECK (rate > 0),
+	updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+	PRIMARY KEY (base, quote),
+	CHECK (base <> quote)
+);
+
+ALTER TABLE settlements ADD COLUMN fx_base TEXT;
+ALTER TABLE settlements ADD COLUMN fx_quote TEXT;
+ALTER TABLE settlements ADD COLUMN fx_rate INTEGER;
+ALTER TABLE settlements ADD COLUMN fx_rate_updated_at TIMESTAMP;
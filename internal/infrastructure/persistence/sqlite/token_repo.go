@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"banana-auction/internal/domain/user"
+)
+
+type TokenRepo struct {
+	db *sql.DB
+}
+
+func NewTokenRepo(db *sql.DB) *TokenRepo {
+	return &TokenRepo{db: db}
+}
+
+func (r *TokenRepo) CreateRefreshToken(ctx context.Context, t user.RefreshToken) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO refresh_tokens (id, family_id, user_id, expires_at)
+		VALUES (?1, ?2, ?3, ?4)`,
+		t.ID, t.FamilyID, t.UserID, t.ExpiresAt.UTC(),
+	)
+	return err
+}
+
+func (r *TokenRepo) GetRefreshToken(ctx context.Context, id string) (user.RefreshToken, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var t user.RefreshToken
+	var usedAt, revokedAt sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, family_id, user_id, expires_at, used_at, revoked_at
+		FROM refresh_tokens WHERE id = ?1`, id,
+	).Scan(&t.ID, &t.FamilyID, &t.UserID, &t.ExpiresAt, &usedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return user.RefreshToken{}, user.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return user.RefreshToken{}, err
+	}
+	if usedAt.Valid {
+		t.UsedAt = &usedAt.Time
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+	return t, nil
+}
+
+func (r *TokenRepo) MarkRefreshTokenUsed(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	res, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE refresh_tokens SET used_at = `+nowSQL+`
+		WHERE id = ?1 AND used_at IS NULL AND revoked_at IS NULL`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *TokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked_at = `+nowSQL+`
+		WHERE family_id = ?1 AND revoked_at IS NULL`, familyID)
+	return err
+}
@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"time"
+
+	"banana-auction/internal/domain/transaction"
+)
+
+const (
+	// maxTxAttempts is how often TxManager.Do runs a unit of work that keeps
+	// timing out on the write lock before giving up.
+	maxTxAttempts = 5
+	txRetryBase   = 10 * time.Millisecond
+)
+
+type txKey struct{}
+
+// queryer is the part of *sql.DB and *sql.Tx the repositories need.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// conn returns the transaction ctx carries, or db outside a unit of work.
+func conn(ctx context.Context, db *sql.DB) queryer {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// inTx runs fn in the transaction ctx carries, or in a new one on db that is
+// committed when fn succeeds. Repositories whose statements must be atomic
+// on their own use it so they still join a caller's unit of work.
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// TxManager implements transaction.Manager on top of database/sql. SQLite
+// transactions are always serializable, so the isolation level is ignored.
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+func (m *TxManager) Do(ctx context.Context, iso transaction.Isolation, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.run(ctx, fn)
+		if err == nil || !IsBusyError(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		// Back off with jitter so the waiting transactions don't all retry
+		// at once.
+		backoff := txRetryBase << (attempt - 1)
+		backoff += rand.N(backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
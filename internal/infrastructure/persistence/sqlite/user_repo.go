@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"banana-auction/internal/domain/user"
+)
+
+type UserRepo struct {
+	db *sql.DB
+}
+
+func NewUserRepo(db *sql.DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+func (r *UserRepo) Create(ctx context.Context, u user.User) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO users (username, password_hash, name, role)
+		VALUES (?1, ?2, ?3, ?4) RETURNING id`,
+		u.Username, u.PasswordHash, u.Name, u.Role,
+	).Scan(&id)
+	if IsDuplicateKeyError(err) {
+		return 0, user.ErrUsernameTaken
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *UserRepo) GetByUsername(ctx context.Context, username string) (user.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var u user.User
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, username, password_hash, name, role
+		FROM users WHERE username = ?1`, username,
+	).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Name, &u.Role)
+	if err == sql.ErrNoRows {
+		return user.User{}, user.ErrNotFound
+	}
+	if err != nil {
+		return user.User{}, err
+	}
+	return u, nil
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, id int) (user.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var u user.User
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, username, password_hash, name, role
+		FROM users WHERE id = ?1`, id,
+	).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Name, &u.Role)
+	if err == sql.ErrNoRows {
+		return user.User{}, user.ErrNotFound
+	}
+	if err != nil {
+		return user.User{}, err
+	}
+	return u, nil
+}
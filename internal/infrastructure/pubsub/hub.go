@@ -0,0 +1,72 @@
+package pubsub
+
+import (
+	"sync"
+
+	"banana-auction/internal/domain/event"
+)
+
+// Hub is an in-process pub/sub broker keyed by auction ID. Publish never
+// blocks: a subscriber whose buffer is full is dropped and its channel
+// closed, so one slow client can't hold up bid placement.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[int]map[*Subscription]struct{}
+	buffer int
+}
+
+type Subscription struct {
+	C         <-chan event.Event
+	ch        chan event.Event
+	auctionID int
+	hub       *Hub
+}
+
+func NewHub(buffer int) *Hub {
+	return &Hub{subs: map[int]map[*Subscription]struct{}{}, buffer: buffer}
+}
+
+func (h *Hub) Subscribe(auctionID int) *Subscription {
+	ch := make(chan event.Event, h.buffer)
+	s := &Subscription{C: ch, ch: ch, auctionID: auctionID, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[auctionID] == nil {
+		h.subs[auctionID] = map[*Subscription]struct{}{}
+	}
+	h.subs[auctionID][s] = struct{}{}
+	return s
+}
+
+func (h *Hub) Publish(e event.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subs[e.AuctionID] {
+		select {
+		case s.ch <- e:
+		default:
+			h.remove(s)
+		}
+	}
+}
+
+// Close unsubscribes s. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// remove must be called with h.mu held.
+func (h *Hub) remove(s *Subscription) {
+	subs := h.subs[s.auctionID]
+	if _, ok := subs[s]; !ok {
+		return
+	}
+	delete(subs, s)
+	if len(subs) == 0 {
+		delete(h.subs, s.auctionID)
+	}
+	close(s.ch)
+}
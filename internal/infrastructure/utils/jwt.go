@@ -1,18 +1,74 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"time"
 
 	"banana-auction/config"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateJWT(userID int) (string, error) {
+func GenerateJWT(userID int, role string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+		"role":    role,
+		"exp":     time.Now().Add(config.GetConfig().AccessTokenTTL).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(config.GetConfig().JwtSecretKey))
-}
\ No newline at end of file
+}
+
+// RefreshClaims identifies a stored refresh token.
+type RefreshClaims struct {
+	TokenID  string
+	FamilyID string
+	UserID   int
+}
+
+// GenerateRefreshJWT signs a refresh token with the refresh key, so it can
+// never be accepted as an access token.
+func GenerateRefreshJWT(c RefreshClaims, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"jti":     c.TokenID,
+		"fam":     c.FamilyID,
+		"user_id": c.UserID,
+		"typ":     "refresh",
+		"exp":     expiresAt.Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.GetConfig().JwtRefreshKey))
+}
+
+func ParseRefreshJWT(tokenStr string) (RefreshClaims, error) {
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.GetConfig().JwtRefreshKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return RefreshClaims{}, errors.New("invalid refresh token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != "refresh" {
+		return RefreshClaims{}, errors.New("invalid refresh token")
+	}
+	tokenID, _ := claims["jti"].(string)
+	familyID, _ := claims["fam"].(string)
+	userID, _ := claims["user_id"].(float64)
+	if tokenID == "" || familyID == "" || userID == 0 {
+		return RefreshClaims{}, errors.New("invalid refresh token")
+	}
+	return RefreshClaims{TokenID: tokenID, FamilyID: familyID, UserID: int(userID)}, nil
+}
+
+// NewTokenID returns a random 128-bit hex identifier.
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,7 +1,14 @@
 package main
 
-import "banana-auction/cmd"
+import (
+	"banana-auction/cmd"
+	"os"
+)
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		cmd.Migrate(os.Args[2:])
+		return
+	}
 	cmd.Serve()
-}
\ No newline at end of file
+}